@@ -1,12 +1,103 @@
 package amqp
 
 import (
-  "fmt"
-  psh "github.com/platformsh/config-reader-go/v2"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	psh "github.com/platformsh/config-reader-go/v2"
 )
 
 // AMQP requires a specfic connection string to connect to RabbitMQ.
 func FormattedCredentials(creds psh.Credential) (string, error) {
-  formatted := fmt.Sprintf("%s://%s:%s@%s:%d/", creds.Scheme, creds.Username, creds.Password, creds.Host, creds.Port)
-  return formatted, nil
+	return FormattedCredentialsWithOptions(creds, Options{})
+}
+
+// Connection string options.  Zero values are left out of the URI, so that
+// the client defaults apply.
+type Options struct {
+	// Connect with TLS, using the amqps scheme.
+	Tls bool
+
+	// The heartbeat interval to negotiate, rounded to seconds.  Any positive
+	// interval is at least one second, as zero turns heartbeats off.
+	Heartbeat time.Duration
+
+	// A name for the connection, shown in the management UI.
+	ConnectionName string
+}
+
+// Formats the connection string with the given options.  The relationship
+// path is used as the virtual host, and the default virtual host ("/") is
+// used if it has none.
+func FormattedCredentialsWithOptions(creds psh.Credential, opts Options) (string, error) {
+	if creds.Host == "" {
+		return "", errors.New("The RabbitMQ relationship has no host")
+	}
+
+	scheme := creds.Scheme
+	if scheme == "" {
+		scheme = "amqp"
+	}
+	if opts.Tls {
+		scheme = "amqps"
+	}
+
+	var b strings.Builder
+
+	b.WriteString(scheme)
+	b.WriteString("://")
+	if creds.Username != "" || creds.Password != "" {
+		b.WriteString(url.UserPassword(creds.Username, creds.Password).String())
+		b.WriteString("@")
+	}
+	b.WriteString(net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port)))
+	b.WriteString("/")
+	if creds.Path != "" && creds.Path != "/" {
+		// Slashes are part of the vhost name, so escape them too.
+		b.WriteString(url.PathEscape(creds.Path))
+	}
+
+	values := make(url.Values)
+	if opts.Heartbeat > 0 {
+		seconds := int(opts.Heartbeat.Round(time.Second) / time.Second)
+		if seconds < 1 {
+			seconds = 1
+		}
+		values.Set("heartbeat", strconv.Itoa(seconds))
+	}
+	if opts.ConnectionName != "" {
+		values.Set("connection_name", opts.ConnectionName)
+	}
+	if query := values.Encode(); query != "" {
+		b.WriteString("?")
+		b.WriteString(query)
+	}
+
+	return b.String(), nil
+}
+
+// Returns the base URL of the management HTTP API, from a relationship to
+// the management endpoint of the service:
+//
+//	relationships:
+//	    rabbitmqadmin: "rabbitmq:management"
+//
+// The API authenticates with the Username and Password of the relationship,
+// which are not included in the URL.
+func ManagementURL(creds psh.Credential) (string, error) {
+	if creds.Scheme != "http" && creds.Scheme != "https" {
+		return "", errors.New("The relationship is not a RabbitMQ management endpoint")
+	}
+
+	u := url.URL{
+		Scheme: creds.Scheme,
+		Host:   net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port)),
+		Path:   "/api/",
+	}
+
+	return u.String(), nil
 }
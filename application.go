@@ -0,0 +1,141 @@
+package platformconfig
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// A worker instance defined in the `workers` section of the application.
+type Worker struct {
+	// For workers listed in the `workers` section this is not part of the
+	// JSON definition, but it gets added to the struct from the JSON object key.
+	Name string `json:"name"`
+
+	Size     string `json:"size"`
+	Disk     int    `json:"disk"`
+	Commands struct {
+		Start string `json:"start"`
+	} `json:"commands"`
+	Relationships map[string]string      `json:"relationships"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// A cron task defined in the `crons` section of the application.
+type Cron struct {
+	// This field is not part of the JSON definition, but it gets added
+	// to the struct from the JSON object key.
+	Name string
+
+	Spec     string `json:"spec"`
+	Cmd      string `json:"cmd"`
+	Commands struct {
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	} `json:"commands"`
+	ShutdownTimeout int `json:"shutdown_timeout"`
+}
+
+// The command run when the cron task fires.
+//
+// Cron tasks may be defined either with the legacy `cmd` key or with
+// `commands.start`; this returns whichever is set.
+func (c Cron) StartCommand() string {
+	if c.Commands.Start != "" {
+		return c.Commands.Start
+	}
+	return c.Cmd
+}
+
+// Returns the workers defined for the application, keyed by name.
+func (p *BuildConfig) Workers() map[string]Worker {
+	return p.workers
+}
+
+// Returns the cron tasks defined for the application, keyed by name.
+func (p *BuildConfig) Crons() map[string]Cron {
+	return p.crons
+}
+
+// Determines if the current instance is a worker rather than the web process.
+func (p *RuntimeConfig) IsWorker() bool {
+	return p.worker != nil
+}
+
+// The name of the worker the current instance runs, or an empty string on
+// the web process.
+func (p *RuntimeConfig) WorkerName() string {
+	if p.worker == nil {
+		return ""
+	}
+	return p.worker.Name
+}
+
+// Returns the definition of the worker the current instance runs.  Its second
+// return is false on the web process.
+func (p *RuntimeConfig) Worker() (Worker, bool) {
+	if p.worker == nil {
+		return Worker{}, false
+	}
+	return *p.worker, true
+}
+
+// Extract the typed sections of PLATFORM_APPLICATION into the build config.
+func (p *BuildConfig) extractApplication() error {
+	if err := decodeApplicationKey(p.application, "workers", &p.workers); err != nil {
+		return err
+	}
+	for name, worker := range p.workers {
+		worker.Name = name
+		p.workers[name] = worker
+	}
+
+	if err := decodeApplicationKey(p.application, "crons", &p.crons); err != nil {
+		return err
+	}
+	for name, cron := range p.crons {
+		cron.Name = name
+		p.crons[name] = cron
+	}
+
+	if err := decodeApplicationKey(p.application, "hooks", &p.hooks); err != nil {
+		return err
+	}
+
+	mounts, err := extractMounts(p.application, p.appDir)
+	if err != nil {
+		return err
+	}
+	p.mounts = mounts
+
+	// On a worker instance the platform adds the definition of the running
+	// worker to the application under the `worker` key.
+	if _, ok := p.application["worker"]; ok {
+		var worker Worker
+		if err := decodeApplicationKey(p.application, "worker", &worker); err != nil {
+			return err
+		}
+		p.worker = &worker
+	}
+
+	return nil
+}
+
+// Map a single key of the untyped application definition onto a typed
+// structure.  Missing keys leave the target untouched.
+func decodeApplicationKey(application map[string]interface{}, key string, target interface{}) error {
+	value, ok := application[key]
+	if !ok || value == nil {
+		return nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(encoded, target); err != nil {
+		return fmt.Errorf("Invalid application %s definition: %s", key, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package platformconfig_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestWorkersAreTyped(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	workers := config.Workers()
+
+	helper.Equals(t, 1, len(workers))
+	helper.Equals(t, "queue", workers["queue"].Name)
+	helper.Equals(t, "S", workers["queue"].Size)
+	helper.Equals(t, 256, workers["queue"].Disk)
+	helper.Equals(t, "./bin/queue-worker", workers["queue"].Commands.Start)
+}
+
+func TestCronsAreTyped(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	crons := config.Crons()
+
+	helper.Equals(t, 2, len(crons))
+	helper.Equals(t, "snapshot", crons["snapshot"].Name)
+	helper.Equals(t, "0 5 * * *", crons["snapshot"].Spec)
+	helper.Equals(t, "./bin/snapshot", crons["snapshot"].StartCommand())
+	helper.Equals(t, "./bin/cleanup", crons["cleanup"].StartCommand())
+	helper.Equals(t, "pkill -f cleanup", crons["cleanup"].Commands.Stop)
+	helper.Equals(t, 30, crons["cleanup"].ShutdownTimeout)
+}
+
+func TestWebInstanceIsNotWorker(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	_, ok := config.Worker()
+
+	helper.Assert(t, !config.IsWorker(), "IsWorker() returned true on the web instance.")
+	helper.Equals(t, "", config.WorkerName())
+	helper.Equals(t, false, ok)
+}
+
+func TestWorkerInstanceIsDetected(t *testing.T) {
+	application := `{"name": "app", "worker": {"name": "queue", "size": "S", "commands": {"start": "./bin/queue-worker"}}}`
+
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_APPLICATION": base64.StdEncoding.EncodeToString([]byte(application)),
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	worker, ok := config.Worker()
+
+	helper.Assert(t, config.IsWorker(), "IsWorker() returned false on a worker instance.")
+	helper.Equals(t, "queue", config.WorkerName())
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "./bin/queue-worker", worker.Commands.Start)
+}
+
+func TestInvalidWorkersDefinitionErrors(t *testing.T) {
+	application := `{"name": "app", "workers": {"queue": {"disk": "lots"}}}`
+
+	_, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{
+		"PLATFORM_APPLICATION": base64.StdEncoding.EncodeToString([]byte(application)),
+	}), "PLATFORM_")
+
+	if err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,121 @@
+// The chromeheadless package reads relationships to the Platform.sh
+// chrome-headless service, and discovers the DevTools endpoint of the browser
+// for libraries such as chromedp.
+//
+// The endpoints use the IP address of the relationship rather than its host
+// name, as the DevTools server rejects requests whose Host header is neither
+// an IP address nor localhost.
+package chromeheadless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The reply of the /json/version DevTools endpoint.
+type Version struct {
+	Browser              string `json:"Browser"`
+	ProtocolVersion      string `json:"Protocol-Version"`
+	UserAgent            string `json:"User-Agent"`
+	V8Version            string `json:"V8-Version"`
+	WebKitVersion        string `json:"WebKit-Version"`
+	WebSocketDebuggerUrl string `json:"webSocketDebuggerUrl"`
+}
+
+// Returns the HTTP debugging endpoint of the browser, such as
+// "http://169.254.44.2:9222".
+func FormattedCredentials(creds psh.Credential) (string, error) {
+	hostPort, err := hostPort(creds)
+	if err != nil {
+		return "", err
+	}
+
+	return "http://" + hostPort, nil
+}
+
+// Returns the DevTools websocket endpoint of the browser, such as
+// "ws://169.254.44.2:9222".  Most DevTools clients accept it and look up
+// the browser websocket URL themselves; see BrowserWebSocketURL() for those
+// that don't.
+func DevToolsURL(creds psh.Credential) (string, error) {
+	hostPort, err := hostPort(creds)
+	if err != nil {
+		return "", err
+	}
+
+	return "ws://" + hostPort, nil
+}
+
+// Queries the /json/version endpoint of the browser.  If httpClient is nil,
+// http.DefaultClient is used.
+func BrowserVersion(ctx context.Context, creds psh.Credential, httpClient *http.Client) (Version, error) {
+	base, err := FormattedCredentials(creds)
+	if err != nil {
+		return Version{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, base+"/json/version", nil)
+	if err != nil {
+		return Version{}, err
+	}
+	req = req.WithContext(ctx)
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Version{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Version{}, fmt.Errorf("Chrome returned HTTP %d for /json/version", resp.StatusCode)
+	}
+
+	var version Version
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return Version{}, err
+	}
+
+	return version, nil
+}
+
+// Discovers the websocket URL of the browser target, such as
+// "ws://169.254.44.2:9222/devtools/browser/<id>".
+func BrowserWebSocketURL(ctx context.Context, creds psh.Credential, httpClient *http.Client) (string, error) {
+	version, err := BrowserVersion(ctx, creds, httpClient)
+	if err != nil {
+		return "", err
+	}
+
+	if version.WebSocketDebuggerUrl == "" {
+		return "", fmt.Errorf("Chrome returned no webSocketDebuggerUrl")
+	}
+
+	return version.WebSocketDebuggerUrl, nil
+}
+
+func hostPort(creds psh.Credential) (string, error) {
+	if !strings.HasPrefix(creds.Type, "chrome-headless:") {
+		return "", fmt.Errorf("Not a chrome-headless relationship: %s", creds.Type)
+	}
+
+	host := creds.Ip
+	if host == "" {
+		host = creds.Host
+	}
+	if host == "" || creds.Port == 0 {
+		return "", fmt.Errorf("The chrome-headless relationship has no address or port")
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(creds.Port)), nil
+}
@@ -0,0 +1,85 @@
+package chromeheadless_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	chromeheadless "github.com/platformsh/config-reader-go/v2/chromeheadless"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestChromeHeadlessEndpoints(t *testing.T) {
+	credentials := helper.ServiceCredential(t, "chrome-headless", "95", "headless")
+
+	formatted, err := chromeheadless.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+	helper.Equals(t, "http://169.254.44.2:9222", formatted)
+
+	devtools, err := chromeheadless.DevToolsURL(credentials)
+	helper.Ok(t, err)
+	helper.Equals(t, "ws://169.254.44.2:9222", devtools)
+
+	// The host name is used only if there is no IP address.
+	credentials.Ip = ""
+	formatted, err = chromeheadless.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+	helper.Equals(t, "http://headless.internal:9222", formatted)
+
+	_, err = chromeheadless.FormattedCredentials(psh.Credential{Type: "redis:6.0", Host: "redis.internal", Port: 6379})
+	helper.Assert(t, err != nil, "FormattedCredentials() accepted a Redis relationship.")
+}
+
+func TestChromeHeadlessDiscoversBrowserWebSocket(t *testing.T) {
+	var hostHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostHeader = r.Host
+		if r.URL.Path != "/json/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"Browser": "HeadlessChrome/95.0.4638.69",
+			"Protocol-Version": "1.3",
+			"webSocketDebuggerUrl": "ws://` + r.Host + `/devtools/browser/abc-123"
+		}`))
+	}))
+	defer server.Close()
+
+	// The host name doesn't resolve: requests must go to the IP address, which
+	// is also what the Host header must be for Chrome to accept them.
+	credentials := helper.PointAt(t, helper.ServiceCredential(t, "chrome-headless", "95", "headless"), server)
+	credentials.Host = "headless.invalid"
+
+	version, err := chromeheadless.BrowserVersion(context.Background(), credentials, nil)
+	helper.Ok(t, err)
+	helper.Equals(t, "HeadlessChrome/95.0.4638.69", version.Browser)
+	helper.Equals(t, "1.3", version.ProtocolVersion)
+	helper.Equals(t, server.Listener.Addr().String(), hostHeader)
+
+	wsURL, err := chromeheadless.BrowserWebSocketURL(context.Background(), credentials, server.Client())
+	helper.Ok(t, err)
+	helper.Equals(t, "ws://"+server.Listener.Addr().String()+"/devtools/browser/abc-123", wsURL)
+}
+
+func TestChromeHeadlessReportsDiscoveryErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/json/version" {
+			w.Write([]byte(`{"Browser": "HeadlessChrome/95.0.4638.69"}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	credentials := helper.PointAt(t, helper.ServiceCredential(t, "chrome-headless", "95", "headless"), server)
+
+	_, err := chromeheadless.BrowserWebSocketURL(context.Background(), credentials, nil)
+	helper.Assert(t, err != nil, "BrowserWebSocketURL() accepted a reply without a websocket URL.")
+
+	server.Close()
+	_, err = chromeheadless.BrowserVersion(context.Background(), credentials, nil)
+	helper.Assert(t, err != nil, "BrowserVersion() returned no error for a closed server.")
+}
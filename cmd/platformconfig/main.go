@@ -0,0 +1,267 @@
+// The platformconfig command inspects Platform.sh environments from the
+// command line.
+//
+// Usage:
+//
+//	platformconfig snapshot > production.json
+//	platformconfig diff staging.json production.json
+//	platformconfig exec -- ./migrate.sh
+//
+// A snapshot is a JSON object of the Platform.sh environment variables of an
+// environment, as the platform sets them.  Take one by running the snapshot
+// subcommand on the environment (for example over SSH).
+//
+// The exec subcommand runs a program with the relationships and routes of the
+// current environment exported as conventional variables such as DATABASE_URL.
+// By default every relationship and route is exported; -map and -route pick
+// specific ones and the names to export them under.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"syscall"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	"github.com/platformsh/config-reader-go/v2/envexport"
+)
+
+const usage = `Usage:
+  platformconfig snapshot [-prefix PLATFORM_]
+  platformconfig diff [-prefix PLATFORM_] [-json] [-show-variables] <before.json> <after.json>
+  platformconfig exec [-prefix PLATFORM_] [-map rel=VAR]... [-route id=VAR]... -- <command> [args...]
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Environ(), os.Stdout, os.Stderr))
+}
+
+// Runs a subcommand and returns the process exit code.
+func run(args []string, environ []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "snapshot":
+		return runSnapshot(args[1:], environ, stdout, stderr)
+	case "diff":
+		return runDiff(args[1:], stdout, stderr)
+	case "exec":
+		return runExec(args[1:], environ, stdout, stderr)
+	}
+
+	fmt.Fprintf(stderr, "Unknown command: %s\n%s", args[0], usage)
+	return 2
+}
+
+// Prints the Platform.sh variables of the current environment as JSON.
+func runSnapshot(args []string, environ []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("snapshot", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	prefix := flags.String("prefix", "PLATFORM_", "the prefix of the Platform.sh variables")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	snapshot := make(psh.EnvList)
+	for _, entry := range environ {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if strings.HasPrefix(parts[0], *prefix) || parts[0] == "PORT" || parts[0] == "SOCKET" {
+			snapshot[parts[0]] = parts[1]
+		}
+	}
+
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(snapshot); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// Prints the differences between two snapshots.  Like diff(1), exits with 1
+// if there are differences.  The values of variables are masked unless
+// -show-variables is given.
+func runDiff(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	prefix := flags.String("prefix", "PLATFORM_", "the prefix of the Platform.sh variables")
+	asJson := flags.Bool("json", false, "print the differences as JSON")
+	showVariables := flags.Bool("show-variables", false, "print the values of variables rather than masking them")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	before, err := loadSnapshot(flags.Arg(0), *prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(0), err)
+		return 2
+	}
+	after, err := loadSnapshot(flags.Arg(1), *prefix)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", flags.Arg(1), err)
+		return 2
+	}
+
+	changes := psh.DiffWithOptions(before, after, psh.DiffOptions{ShowVariables: *showVariables})
+
+	if *asJson {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "    ")
+		encoder.Encode(changes)
+	} else {
+		printChanges(stdout, changes)
+	}
+
+	if len(changes) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Runs a command with relationships and routes exported as variables, and
+// exits with its exit code.
+func runExec(args []string, environ []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("exec", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	prefix := flags.String("prefix", "PLATFORM_", "the prefix of the Platform.sh variables")
+	relationships := make(mappingFlag)
+	flags.Var(relationships, "map", "export a relationship as `rel=VAR`, giving VAR_URL, VAR_HOST, etc.")
+	routes := make(mappingFlag)
+	flags.Var(routes, "route", "export the URL of a route as `id=VAR`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	env := make(psh.EnvList)
+	for _, entry := range environ {
+		if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+
+	config, err := psh.NewRuntimeConfigReal(func(key string) string {
+		return env[key]
+	}, *prefix)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	mapping := envexport.DefaultMapping(config)
+	if len(relationships) > 0 || len(routes) > 0 {
+		mapping = envexport.Mapping{Relationships: relationships, Routes: routes}
+	}
+
+	exported, err := envexport.Environ(config, mapping)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cmd := exec.Command(flags.Arg(0), flags.Args()[1:]...)
+	cmd.Env = append(append([]string{}, environ...), exported...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+				return status.ExitStatus()
+			}
+			return 1
+		}
+		fmt.Fprintln(stderr, err)
+		return 127
+	}
+
+	return 0
+}
+
+// A repeatable "key=value" flag.
+type mappingFlag map[string]string
+
+func (m mappingFlag) String() string {
+	pairs := make([]string, 0, len(m))
+	for key, val := range m {
+		pairs = append(pairs, key+"="+val)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+func (m mappingFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected name=VAR, got %q", value)
+	}
+	m[parts[0]] = parts[1]
+
+	return nil
+}
+
+func printChanges(out io.Writer, changes []psh.Change) {
+	sections := make([]string, 0)
+	bySection := make(map[string][]psh.Change)
+	for _, change := range changes {
+		if _, ok := bySection[change.Section]; !ok {
+			sections = append(sections, change.Section)
+		}
+		bySection[change.Section] = append(bySection[change.Section], change)
+	}
+	sort.Strings(sections)
+
+	for _, section := range sections {
+		fmt.Fprintf(out, "%s:\n", section)
+		for _, change := range bySection[section] {
+			switch change.Kind {
+			case psh.ChangeAdded:
+				fmt.Fprintln(out, strings.TrimRight("  + "+change.Key+" "+change.New, " "))
+			case psh.ChangeRemoved:
+				fmt.Fprintln(out, strings.TrimRight("  - "+change.Key+" "+change.Old, " "))
+			default:
+				fmt.Fprintf(out, "  ~ %s %q -> %q\n", change.Key, change.Old, change.New)
+			}
+		}
+	}
+}
+
+func loadSnapshot(file string, prefix string) (*psh.RuntimeConfig, error) {
+	contents, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot psh.EnvList
+	if err := json.Unmarshal(contents, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return psh.NewRuntimeConfigReal(func(key string) string {
+		return snapshot[key]
+	}, prefix)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func writeSnapshot(t *testing.T, dir string, name string, env psh.EnvList) string {
+	contents, err := json.Marshal(env)
+	helper.Ok(t, err)
+
+	file := filepath.Join(dir, name)
+	helper.Ok(t, ioutil.WriteFile(file, contents, 0644))
+
+	return file
+}
+
+func TestSnapshotPrintsPlatformVariables(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"snapshot"}, []string{
+		"PLATFORM_BRANCH=main",
+		"PLATFORM_VARIABLES=e30=",
+		"PORT=8080",
+		"HOME=/app",
+	}, &stdout, &stderr)
+
+	helper.Equals(t, 0, code)
+
+	var snapshot psh.EnvList
+	helper.Ok(t, json.Unmarshal(stdout.Bytes(), &snapshot))
+	helper.Equals(t, psh.EnvList{
+		"PLATFORM_BRANCH":    "main",
+		"PLATFORM_VARIABLES": "e30=",
+		"PORT":               "8080",
+	}, snapshot)
+}
+
+func TestDiffPrintsChanges(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-reader-go")
+	helper.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	before := writeSnapshot(t, dir, "before.json", psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "staging",
+		"PLATFORM_VARIABLES":        "eyJhIjogIjEifQ==",
+	})
+	after := writeSnapshot(t, dir, "after.json", psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "master",
+		"PLATFORM_VARIABLES":        "eyJiIjogIjIifQ==",
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"diff", before, after}, nil, &stdout, &stderr)
+
+	helper.Equals(t, 1, code)
+	helper.Equals(t, "environment:\n  ~ branch \"staging\" -> \"master\"\nvariables:\n  - a ******\n  + b ******\n", stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"diff", "-show-variables", before, after}, nil, &stdout, &stderr)
+
+	helper.Equals(t, 1, code)
+	helper.Equals(t, "environment:\n  ~ branch \"staging\" -> \"master\"\nvariables:\n  - a 1\n  + b 2\n", stdout.String())
+}
+
+func TestDiffOfSameSnapshotSucceeds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-reader-go")
+	helper.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	snapshot := writeSnapshot(t, dir, "snapshot.json", psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "master",
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"diff", "-json", snapshot, snapshot}, nil, &stdout, &stderr)
+
+	helper.Equals(t, 0, code)
+	helper.Equals(t, "[]\n", stdout.String())
+}
+
+func TestDiffWithMissingSnapshotFails(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"diff", "missing.json", "missing.json"}, nil, &stdout, &stderr)
+
+	helper.Equals(t, 2, code)
+}
+
+func TestExecExportsRelationships(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("No shell available.")
+	}
+
+	env := helper.NewEnvBuilder().
+		WithRelationship("database", psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306, Username: "user", Path: "main"}).
+		EnvList()
+	environ := []string{"PATH=" + os.Getenv("PATH")}
+	for key, val := range env {
+		environ = append(environ, key+"="+val)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"exec", "--", "sh", "-c", "echo $DATABASE_URL"}, environ, &stdout, &stderr)
+	helper.Equals(t, 0, code)
+	helper.Equals(t, "mysql://user@database.internal:3306/main\n", stdout.String())
+
+	stdout.Reset()
+	code = run([]string{"exec", "-map", "database=DB", "--", "sh", "-c", "echo $DB_HOST $DATABASE_URL; exit 3"}, environ, &stdout, &stderr)
+	helper.Equals(t, 3, code)
+	helper.Equals(t, "database.internal\n", stdout.String())
+
+	code = run([]string{"exec", "-map", "missing=MISSING", "--", "true"}, environ, &stdout, &stderr)
+	helper.Equals(t, 2, code)
+}
@@ -0,0 +1,97 @@
+package platformconfig
+
+import (
+	"context"
+	"net/http"
+)
+
+// The type of the context keys, unexported to prevent collisions with keys
+// defined in other packages.
+type contextKey int
+
+const (
+	buildConfigKey contextKey = iota
+	runtimeConfigKey
+	runtimeInfoKey
+)
+
+// Returns a copy of ctx that carries a RuntimeConfig.
+func NewContext(ctx context.Context, config *RuntimeConfig) context.Context {
+	return context.WithValue(ctx, runtimeConfigKey, config)
+}
+
+// Returns the RuntimeConfig carried by ctx.  Its second return is false if
+// there is none.
+func FromContext(ctx context.Context) (*RuntimeConfig, bool) {
+	config, ok := ctx.Value(runtimeConfigKey).(*RuntimeConfig)
+	return config, ok && config != nil
+}
+
+// Returns a copy of ctx that carries a BuildConfig.
+func NewBuildContext(ctx context.Context, config *BuildConfig) context.Context {
+	return context.WithValue(ctx, buildConfigKey, config)
+}
+
+// Returns the BuildConfig carried by ctx, or the build part of the
+// RuntimeConfig carried by ctx if there is no BuildConfig.  Its second return
+// is false if there is neither.
+func BuildFromContext(ctx context.Context) (*BuildConfig, bool) {
+	if config, ok := ctx.Value(buildConfigKey).(*BuildConfig); ok && config != nil {
+		return config, true
+	}
+
+	if config, ok := FromContext(ctx); ok {
+		return &config.BuildConfig, true
+	}
+
+	return nil, false
+}
+
+// Returns a copy of ctx that carries a RuntimeInfo, such as a fake config in
+// tests.
+func NewRuntimeInfoContext(ctx context.Context, info RuntimeInfo) context.Context {
+	return context.WithValue(ctx, runtimeInfoKey, info)
+}
+
+// Returns the RuntimeInfo carried by ctx, or the RuntimeConfig carried by ctx
+// if there is no RuntimeInfo.  Its second return is false if there is neither.
+func RuntimeInfoFromContext(ctx context.Context) (RuntimeInfo, bool) {
+	if info, ok := ctx.Value(runtimeInfoKey).(RuntimeInfo); ok && info != nil {
+		return info, true
+	}
+
+	if config, ok := FromContext(ctx); ok {
+		return config, true
+	}
+
+	return nil, false
+}
+
+// Returns HTTP middleware that adds a RuntimeConfig to the context of every
+// request, for handlers to retrieve with FromContext().
+func Middleware(config *RuntimeConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), config)))
+		})
+	}
+}
+
+// A provider for dependency injection frameworks, returning the shared
+// BuildConfig.
+func ProvideBuildConfig() (*BuildConfig, error) {
+	return SharedBuildConfig()
+}
+
+// A provider for dependency injection frameworks, returning the shared
+// RuntimeConfig.
+func ProvideRuntimeConfig() (*RuntimeConfig, error) {
+	return SharedRuntimeConfig()
+}
+
+// The providers of this package, ready to be registered with a dependency
+// injection framework, for example fx.Provide(psh.Providers...).
+var Providers = []interface{}{
+	ProvideBuildConfig,
+	ProvideRuntimeConfig,
+}
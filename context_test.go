@@ -0,0 +1,90 @@
+package platformconfig_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestRuntimeConfigRoundTripsThroughContext(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	ctx := psh.NewContext(context.Background(), config)
+
+	found, ok := psh.FromContext(ctx)
+	helper.Equals(t, true, ok)
+	helper.Assert(t, found == config, "FromContext() returned a different config.")
+
+	build, ok := psh.BuildFromContext(ctx)
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "app", build.ApplicationName())
+}
+
+func TestBuildConfigRoundTripsThroughContext(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	ctx := psh.NewBuildContext(context.Background(), config)
+
+	found, ok := psh.BuildFromContext(ctx)
+	helper.Equals(t, true, ok)
+	helper.Assert(t, found == config, "BuildFromContext() returned a different config.")
+
+	_, ok = psh.FromContext(ctx)
+	helper.Equals(t, false, ok)
+}
+
+func TestEmptyContextHasNoConfig(t *testing.T) {
+	_, ok := psh.FromContext(context.Background())
+	helper.Equals(t, false, ok)
+
+	_, ok = psh.BuildFromContext(context.Background())
+	helper.Equals(t, false, ok)
+}
+
+func TestMiddlewareAddsConfigToRequests(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	var found *psh.RuntimeConfig
+	handler := psh.Middleware(config)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		found, _ = psh.FromContext(r.Context())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	helper.Assert(t, found == config, "The handler did not receive the config.")
+}
+
+func TestProvidersReturnSharedConfig(t *testing.T) {
+	defer setenv(t, psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "main",
+	})()
+	psh.ResetShared()
+
+	provided, err := psh.ProvideRuntimeConfig()
+	helper.Ok(t, err)
+	shared, err := psh.SharedRuntimeConfig()
+	helper.Ok(t, err)
+
+	helper.Assert(t, provided == shared, "ProvideRuntimeConfig() did not return the shared config.")
+	helper.Equals(t, 2, len(psh.Providers))
+}
+
+func TestRuntimeInfoFromContextFallsBackToRuntimeConfig(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	info, ok := psh.RuntimeInfoFromContext(psh.NewContext(context.Background(), config))
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "feature-x", info.Branch())
+
+	_, ok = psh.RuntimeInfoFromContext(context.Background())
+	helper.Equals(t, false, ok)
+}
@@ -0,0 +1,180 @@
+package platformconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// What the JSON of a relationship holds beyond the fields of Credential.
+//
+// Copies of a Credential share their CredentialRaw: replace it rather than
+// modify it in place.
+type CredentialRaw struct {
+	// Every key of the query, including is_master if it was set, when the
+	// query has other keys than is_master.
+	Query map[string]interface{}
+
+	// The JSON names of the fields that were null rather than empty, such as
+	// "password", sorted.
+	Nulls []string
+
+	// The keys that have no field in Credential, such as "epoch" or
+	// "instance_ips", exactly as they were in the JSON.
+	Extra map[string]json.RawMessage
+}
+
+// Credential without its JSON methods, for the default encoding of its fields.
+type credentialFields Credential
+
+// The JSON keys of the Credential fields, and the encoding of their zero
+// values.
+var credentialZeros = func() map[string]json.RawMessage {
+	encoded, err := json.Marshal(credentialFields{})
+	if err != nil {
+		panic(err)
+	}
+
+	var zeros map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &zeros); err != nil {
+		panic(err)
+	}
+
+	return zeros
+}()
+
+// Returns a key of the relationship that has no field in Credential, such as
+// "instance_ips", as raw JSON, and whether it is set.
+func (c Credential) Extra(key string) (json.RawMessage, bool) {
+	if c.Raw == nil {
+		return nil, false
+	}
+
+	val, ok := c.Raw.Extra[key]
+	return val, ok
+}
+
+// Returns a copy of the whole relationship query.  Query.IsMaster is the key
+// most services set; this gives access to the others.
+func (c Credential) QueryValues() map[string]interface{} {
+	if c.Raw == nil || c.Raw.Query == nil {
+		return map[string]interface{}{"is_master": c.Query.IsMaster}
+	}
+
+	values := make(map[string]interface{}, len(c.Raw.Query)+1)
+	for key, val := range c.Raw.Query {
+		values[key] = val
+	}
+	if _, ok := values["is_master"]; ok || c.Query.IsMaster {
+		values["is_master"] = c.Query.IsMaster
+	}
+
+	return values
+}
+
+// Returns the value of a key of the relationship query, and whether it is
+// set.  See QueryValues().
+func (c Credential) QueryValue(key string) (interface{}, bool) {
+	val, ok := c.QueryValues()[key]
+	return val, ok
+}
+
+// Determines whether a field was null in the JSON the credential was decoded
+// from, as opposed to empty or missing.  Key is the JSON name of the field:
+// the platform sets "username", "password", "path" and "fragment" to null for
+// services that have none.
+func (c Credential) IsNull(key string) bool {
+	if c.Raw == nil {
+		return false
+	}
+
+	i := sort.SearchStrings(c.Raw.Nulls, key)
+	return i < len(c.Raw.Nulls) && c.Raw.Nulls[i] == key
+}
+
+func (c *Credential) UnmarshalJSON(data []byte) error {
+	var fields credentialFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*c = Credential(fields)
+
+	var extra CredentialRaw
+	for key, val := range raw {
+		// Field names are matched case-insensitively, as encoding/json does.
+		known := strings.ToLower(key)
+		switch {
+		case credentialZeros[known] == nil:
+			if extra.Extra == nil {
+				extra.Extra = make(map[string]json.RawMessage)
+			}
+			extra.Extra[key] = val
+		case isNull(val):
+			extra.Nulls = append(extra.Nulls, known)
+		case known == "query":
+			var query map[string]interface{}
+			if err := json.Unmarshal(val, &query); err != nil {
+				return err
+			}
+			if _, ok := query["is_master"]; !ok || len(query) > 1 {
+				extra.Query = query
+			}
+		}
+	}
+	sort.Strings(extra.Nulls)
+
+	if extra.Query != nil || extra.Nulls != nil || extra.Extra != nil {
+		c.Raw = &extra
+	}
+
+	return nil
+}
+
+// Encodes the credential the way the platform does.  A credential decoded
+// from JSON is encoded back to the same keys and values, with the missing
+// fields added: fields that were null stay so unless they have been set since.
+func (c Credential) MarshalJSON() ([]byte, error) {
+	encoded, err := json.Marshal(credentialFields(c))
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	if c.Raw != nil {
+		if c.Raw.Query != nil {
+			if fields["query"], err = json.Marshal(c.QueryValues()); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, key := range c.Raw.Nulls {
+			if zero, ok := credentialZeros[key]; ok && reflect.DeepEqual(fields[key], zero) {
+				fields[key] = json.RawMessage("null")
+			}
+		}
+
+		for key, val := range c.Raw.Extra {
+			if _, ok := fields[key]; !ok {
+				fields[key] = val
+			}
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+func isNull(data json.RawMessage) bool {
+	return bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+}
@@ -0,0 +1,191 @@
+package platformconfig_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+const vaultCredential = `{
+	"scheme": "http",
+	"cluster": "abc-master",
+	"service": "vault-kms",
+	"username": null,
+	"password": "s.token",
+	"fragment": null,
+	"path": "",
+	"public": false,
+	"ip": "169.254.70.1",
+	"rel": "sign",
+	"hostname": "abc.vault-kms.service._.eu-3.platformsh.site",
+	"host": "vault-kms.internal",
+	"port": 8200,
+	"type": "vault-kms:1.6",
+	"query": {"is_master": true, "shard": 2},
+	"epoch": 0,
+	"instance_ips": ["169.254.70.1"],
+	"policy": "sign_and_verify"
+}`
+
+// Decode JSON into a generic value, for comparisons that ignore key order.
+func decodeGeneric(t *testing.T, data []byte) interface{} {
+	var generic interface{}
+	helper.Ok(t, json.Unmarshal(data, &generic))
+	return generic
+}
+
+func TestCredentialKeepsQueryAndExtraKeys(t *testing.T) {
+	var creds psh.Credential
+	helper.Ok(t, json.Unmarshal([]byte(vaultCredential), &creds))
+
+	helper.Assert(t, creds.Query.IsMaster, "Query.IsMaster was not decoded.")
+	shard, ok := creds.QueryValue("shard")
+	helper.Assert(t, ok, "The shard query key was lost.")
+	helper.Equals(t, float64(2), shard)
+	helper.Equals(t, map[string]interface{}{"is_master": true, "shard": float64(2)}, creds.QueryValues())
+
+	// QueryValues() returns a copy.
+	creds.QueryValues()["shard"] = float64(3)
+	shard, _ = creds.QueryValue("shard")
+	helper.Equals(t, float64(2), shard)
+
+	helper.Equals(t, 3, len(creds.Raw.Extra))
+	policy, ok := creds.Extra("policy")
+	helper.Assert(t, ok, "The policy key was lost.")
+	helper.Equals(t, json.RawMessage(`"sign_and_verify"`), policy)
+	ips, _ := creds.Extra("instance_ips")
+	helper.Equals(t, json.RawMessage(`["169.254.70.1"]`), ips)
+
+	_, ok = psh.Credential{}.Extra("policy")
+	helper.Assert(t, !ok, "A credential built in code has an extra key.")
+	helper.Equals(t, map[string]interface{}{"is_master": false}, psh.Credential{}.QueryValues())
+}
+
+func TestDecodedCredentialEqualsLiteral(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	decoded, err := config.Credentials("database")
+	helper.Ok(t, err)
+
+	literal := psh.Credential{
+		Scheme:   "mysql",
+		Cluster:  "dtsla3sy7euhc-master-7rqtwti",
+		Service:  "mysql",
+		Username: "user",
+		Host:     "database.internal",
+		Path:     "main",
+		Ip:       "169.254.81.252",
+		Rel:      "mysql",
+		Type:     "mysql:10.2",
+		Port:     3306,
+		Hostname: "ihq65cmi2m7nd3svqpcrbjchyy.mysql.service._.us-2.platformsh.site",
+		Raw:      &psh.CredentialRaw{Nulls: []string{"fragment"}},
+	}
+	literal.Query.IsMaster = true
+	helper.Equals(t, literal, decoded)
+
+	copied := literal
+	copied.Raw = decoded.Raw
+	helper.Assert(t, copied == decoded, "A decoded credential differs from a copy of its fields.")
+
+	// Credentials whose JSON holds nothing more than their fields have no Raw.
+	built := psh.Credential{Scheme: "redis", Host: "redis.internal", Port: 6379}
+	encoded, err := json.Marshal(built)
+	helper.Ok(t, err)
+
+	var roundTripped psh.Credential
+	helper.Ok(t, json.Unmarshal(encoded, &roundTripped))
+	helper.Assert(t, built == roundTripped, "A decoded credential differs from the one it was encoded from.")
+}
+
+func TestCredentialDistinguishesNullFromEmpty(t *testing.T) {
+	var creds psh.Credential
+	helper.Ok(t, json.Unmarshal([]byte(vaultCredential), &creds))
+
+	helper.Equals(t, "", creds.Username)
+	helper.Assert(t, creds.IsNull("username"), "A null username was not reported as null.")
+	helper.Assert(t, creds.IsNull("fragment"), "A null fragment was not reported as null.")
+	helper.Assert(t, !creds.IsNull("path"), "An empty path was reported as null.")
+	helper.Assert(t, !psh.Credential{}.IsNull("username"), "A credential built in code has a null field.")
+}
+
+func TestCredentialRoundTrips(t *testing.T) {
+	var creds psh.Credential
+	helper.Ok(t, json.Unmarshal([]byte(vaultCredential), &creds))
+
+	encoded, err := json.Marshal(creds)
+	helper.Ok(t, err)
+	helper.Equals(t, decodeGeneric(t, []byte(vaultCredential)), decodeGeneric(t, encoded))
+
+	// Fields set after decoding replace null values.
+	creds.Username = "admin"
+	creds.Query.IsMaster = false
+	encoded, err = json.Marshal(&creds)
+	helper.Ok(t, err)
+
+	generic := decodeGeneric(t, encoded).(map[string]interface{})
+	helper.Equals(t, "admin", generic["username"])
+	helper.Equals(t, nil, generic["fragment"])
+	helper.Equals(t, map[string]interface{}{"is_master": false, "shard": float64(2)}, generic["query"])
+}
+
+func TestCredentialFixturesRoundTrip(t *testing.T) {
+	files := []string{filepath.Join("testdata", "PLATFORM_RELATIONSHIPS.json")}
+	for service, versions := range helper.ServiceFixtures(t) {
+		for _, version := range versions {
+			files = append(files, filepath.Join("testdata", "services", service, version+".json"))
+		}
+	}
+
+	encodedZero, err := json.Marshal(psh.Credential{})
+	helper.Ok(t, err)
+	zero := decodeGeneric(t, encodedZero).(map[string]interface{})
+
+	for _, file := range files {
+		original, err := ioutil.ReadFile(file)
+		helper.Ok(t, err)
+
+		var rels psh.Credentials
+		helper.Ok(t, json.Unmarshal(original, &rels))
+
+		encoded, err := json.Marshal(rels)
+		helper.Ok(t, err)
+
+		// Every key comes back as it was, and missing fields are added with
+		// their zero value.
+		expected := decodeGeneric(t, original).(map[string]interface{})
+		for _, instances := range expected {
+			for _, instance := range instances.([]interface{}) {
+				for key, val := range zero {
+					if _, ok := instance.(map[string]interface{})[key]; !ok {
+						instance.(map[string]interface{})[key] = val
+					}
+				}
+			}
+		}
+		helper.Equals(t, expected, decodeGeneric(t, encoded))
+	}
+}
+
+func TestCredentialBuiltInCodeEncodesEveryField(t *testing.T) {
+	creds := psh.Credential{Scheme: "redis", Host: "redis.internal", Port: 6379}
+	creds.Query.IsMaster = true
+
+	encoded, err := json.Marshal(creds)
+	helper.Ok(t, err)
+
+	generic := decodeGeneric(t, encoded).(map[string]interface{})
+	helper.Equals(t, 15, len(generic))
+	helper.Equals(t, "", generic["password"])
+	helper.Equals(t, map[string]interface{}{"is_master": true}, generic["query"])
+
+	var decoded psh.Credential
+	helper.Ok(t, json.Unmarshal(encoded, &decoded))
+	helper.Equals(t, "redis.internal", decoded.Host)
+	helper.Assert(t, decoded.Query.IsMaster, "Query.IsMaster was not round-tripped.")
+}
@@ -0,0 +1,252 @@
+package platformconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// The possible values of Change.Kind.
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "changed"
+)
+
+// The value reported in place of anything that looks like a secret.
+const MaskedValue = "******"
+
+// A single difference between two configurations.
+type Change struct {
+	// The part of the configuration that changed: "environment",
+	// "application", "relationships", "routes" or "variables".
+	Section string `json:"section"`
+
+	// The path of the value that changed within its section.  For variables
+	// this is the variable name, for the other sections a dotted path such as
+	// "database.0.host".  Added and removed relationships and routes are
+	// reported once, by name.
+	Key string `json:"key"`
+
+	// One of ChangeAdded, ChangeRemoved or ChangeModified.
+	Kind string `json:"kind"`
+
+	// The old and new values, where they can be represented as a string.
+	// Passwords and other values that look like secrets are masked, as are
+	// variables unless DiffOptions.ShowVariables is set.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// Words that mark a value as secret when they appear in its path.
+var secretWords = []string{"password", "passwd", "secret", "token", "api_key", "apikey", "private_key", "credential", "basic_auth"}
+
+// Options for DiffWithOptions().
+type DiffOptions struct {
+	// Report the values of variables.  By default they are all masked, as
+	// variables often hold credentials under names that don't look secret,
+	// such as "env:DATABASE_URL".  Variables whose names look secret are
+	// masked regardless.
+	ShowVariables bool
+}
+
+// Lists the differences between two runtime configurations, such as the
+// staging and production environments of a project, grouped by section and
+// sorted by key.  The values of variables are masked.
+//
+// Routes are matched by ID, or by their original URL if they have no ID, so
+// that environments with different domains can be compared.
+func Diff(before *RuntimeConfig, after *RuntimeConfig) []Change {
+	return DiffWithOptions(before, after, DiffOptions{})
+}
+
+// Lists the differences between two runtime configurations with the given
+// options.  See Diff().
+func DiffWithOptions(before *RuntimeConfig, after *RuntimeConfig, opts DiffOptions) []Change {
+	changes := diffStrings("environment", environmentValues(before), environmentValues(after), false)
+
+	changes = append(changes, diffTree("application", before.application, after.application)...)
+
+	relsBefore := make(map[string]interface{})
+	for name, creds := range before.credentials {
+		relsBefore[name] = creds
+	}
+	relsAfter := make(map[string]interface{})
+	for name, creds := range after.credentials {
+		relsAfter[name] = creds
+	}
+	changes = append(changes, diffTree("relationships", relsBefore, relsAfter)...)
+
+	changes = append(changes, diffTree("routes", routesByKey(before.routes), routesByKey(after.routes))...)
+
+	changes = append(changes, diffStrings("variables", before.variables, after.variables, !opts.ShowVariables)...)
+
+	return changes
+}
+
+// The simple values of a configuration that are worth comparing.
+func environmentValues(p *RuntimeConfig) map[string]string {
+	return map[string]string{
+		"application_name": p.applicationName,
+		"tree_id":          p.treeId,
+		"branch":           p.branch,
+		"environment":      p.environment,
+		"environment_type": p.envType,
+		"document_root":    p.documentRoot,
+		"smtp_host":        p.smtpHost,
+		"mode":             p.mode,
+		"port":             p.port,
+		"socket":           p.socket,
+	}
+}
+
+// Index routes by ID or original URL, leaving out the environment-specific URL.
+func routesByKey(routes Routes) map[string]interface{} {
+	ret := make(map[string]interface{})
+
+	for _, route := range routes {
+		key := route.Id
+		if key == "" {
+			key = route.OriginalUrl
+		}
+
+		value := *route
+		value.Url = ""
+		ret[key] = value
+	}
+
+	return ret
+}
+
+// Compare two maps of structured values.  Entries only on one side are
+// reported as a whole, entries on both sides leaf by leaf.
+func diffTree(section string, before map[string]interface{}, after map[string]interface{}) []Change {
+	changes := make([]Change, 0)
+
+	for _, key := range unionKeys(before, after) {
+		valBefore, inBefore := before[key]
+		valAfter, inAfter := after[key]
+
+		if !inBefore || !inAfter {
+			changes = append(changes, Change{Section: section, Key: key, Kind: changeKind(inBefore, inAfter, false)})
+			continue
+		}
+
+		leavesBefore := make(map[string]string)
+		flatten(key, valBefore, leavesBefore)
+		leavesAfter := make(map[string]string)
+		flatten(key, valAfter, leavesAfter)
+
+		changes = append(changes, diffStrings(section, leavesBefore, leavesAfter, false)...)
+	}
+
+	return changes
+}
+
+// Compare two maps of strings.  Values are masked if maskAll is set or their
+// key looks secret.
+func diffStrings(section string, before map[string]string, after map[string]string, maskAll bool) []Change {
+	changes := make([]Change, 0)
+
+	for _, key := range unionKeys(before, after) {
+		valBefore, inBefore := before[key]
+		valAfter, inAfter := after[key]
+		if kind := changeKind(inBefore, inAfter, valBefore == valAfter); kind != "" {
+			if maskAll || isSecret(key) {
+				valBefore, valAfter = maskValue(valBefore), maskValue(valAfter)
+			}
+			changes = append(changes, Change{Section: section, Key: key, Kind: kind, Old: valBefore, New: valAfter})
+		}
+	}
+
+	return changes
+}
+
+func changeKind(inBefore bool, inAfter bool, equal bool) string {
+	switch {
+	case inBefore && !inAfter:
+		return ChangeRemoved
+	case !inBefore && inAfter:
+		return ChangeAdded
+	case inBefore && inAfter && !equal:
+		return ChangeModified
+	}
+	return ""
+}
+
+// Flatten a value into dotted paths and their string representation.
+func flatten(prefix string, value interface{}, leaves map[string]string) {
+	// Round-trip through JSON so that structs are walked like the maps and
+	// slices they were decoded from.
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		leaves[prefix] = fmt.Sprintf("%v", value)
+		return
+	}
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var generic interface{}
+	if err := decoder.Decode(&generic); err != nil {
+		leaves[prefix] = string(encoded)
+		return
+	}
+
+	flattenGeneric(prefix, generic, leaves)
+}
+
+func flattenGeneric(prefix string, value interface{}, leaves map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			flattenGeneric(prefix+"."+key, child, leaves)
+		}
+	case []interface{}:
+		for i, child := range v {
+			flattenGeneric(fmt.Sprintf("%s.%d", prefix, i), child, leaves)
+		}
+	case nil:
+		leaves[prefix] = "null"
+	case string:
+		leaves[prefix] = v
+	default:
+		leaves[prefix] = fmt.Sprintf("%v", v)
+	}
+}
+
+func isSecret(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range secretWords {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskValue(value string) string {
+	if value == "" {
+		return ""
+	}
+	return MaskedValue
+}
+
+// Returns the sorted keys of two maps with string keys.
+func unionKeys(a interface{}, b interface{}) []string {
+	seen := make(map[string]bool)
+	for _, m := range []interface{}{a, b} {
+		for _, key := range reflect.ValueOf(m).MapKeys() {
+			seen[key.String()] = true
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,83 @@
+package platformconfig
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Where to reach another application of the project.
+type AppEndpoint struct {
+	// Whether the application is reached through a relationship, over the
+	// internal network, rather than through the public router.
+	Internal bool
+
+	// The relationship to the application, if Internal is true.
+	Relationship string
+	Credential   Credential
+
+	// The base URL to send requests to: the internal address if Internal is
+	// true, otherwise the URL of the first of Routes.
+	URL string
+
+	// The public routes to the application, the primary route first, then
+	// HTTPS routes before HTTP ones.
+	Routes []Route
+}
+
+// Finds how to reach another application of the project by name.
+//
+// A relationship to the application is preferred, so that internal calls
+// don't go through the public router.  Otherwise the routes with the
+// application as upstream are used.  It is an error for the application to
+// have neither.
+func (p *RuntimeConfig) DiscoverApp(appName string) (AppEndpoint, error) {
+	endpoint := AppEndpoint{Routes: sortedRoutes(p.UpstreamRoutesForApp(appName))}
+
+	for _, name := range p.Relationships() {
+		if len(p.credentials[name]) == 0 {
+			continue
+		}
+
+		creds := p.credentials[name][0]
+		if creds.Service != appName || (creds.Scheme != "http" && creds.Scheme != "https") {
+			continue
+		}
+
+		endpoint.Internal = true
+		endpoint.Relationship = name
+		endpoint.Credential = creds
+		endpoint.URL = creds.Scheme + "://" + net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port))
+
+		return endpoint, nil
+	}
+
+	if len(endpoint.Routes) == 0 {
+		return AppEndpoint{}, fmt.Errorf("No relationship or route to application: %s", appName)
+	}
+	endpoint.URL = strings.TrimSuffix(endpoint.Routes[0].Url, "/")
+
+	return endpoint, nil
+}
+
+func sortedRoutes(routes Routes) []Route {
+	ret := make([]Route, 0, len(routes))
+	for _, route := range routes {
+		ret = append(ret, *route)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Primary != ret[j].Primary {
+			return ret[i].Primary
+		}
+		httpsI, httpsJ := strings.HasPrefix(ret[i].Url, "https:"), strings.HasPrefix(ret[j].Url, "https:")
+		if httpsI != httpsJ {
+			return httpsI
+		}
+		return ret[i].Url < ret[j].Url
+	})
+
+	return ret
+}
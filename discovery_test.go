@@ -0,0 +1,58 @@
+package platformconfig_test
+
+import (
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func multiAppConfig(t *testing.T) *psh.RuntimeConfig {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().
+		WithRelationship("backend", psh.Credential{Scheme: "http", Service: "api", Host: "api.internal", Port: 80, Type: "golang:1.14"}).
+		WithRelationship("empty").
+		WithRelationship("database", psh.Credential{Scheme: "mysql", Service: "database", Host: "database.internal", Port: 3306}).
+		WithRoute("http://api.example.com/", psh.Route{Type: "redirect", OriginalUrl: "http://api.{default}/"}).
+		WithRoute("https://api.example.com/", psh.Route{Type: "upstream", Upstream: "api:http", OriginalUrl: "https://api.{default}/"}).
+		WithRoute("https://admin.example.com/", psh.Route{Type: "upstream", Upstream: "admin:http", OriginalUrl: "https://admin.{default}/"}).
+		WithRoute("http://admin.example.com/", psh.Route{Type: "upstream", Upstream: "admin:http", OriginalUrl: "http://admin.{default}/"}).
+		WithRoute("https://www.example.com/admin/", psh.Route{Type: "upstream", Upstream: "admin:http", OriginalUrl: "https://www.{default}/admin/", Primary: true}).
+		Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	return config
+}
+
+func TestDiscoverAppPrefersRelationship(t *testing.T) {
+	endpoint, err := multiAppConfig(t).DiscoverApp("api")
+	helper.Ok(t, err)
+
+	helper.Assert(t, endpoint.Internal, "DiscoverApp() didn't use the relationship.")
+	helper.Equals(t, "backend", endpoint.Relationship)
+	helper.Equals(t, "api.internal", endpoint.Credential.Host)
+	helper.Equals(t, "http://api.internal:80", endpoint.URL)
+	helper.Equals(t, 1, len(endpoint.Routes))
+	helper.Equals(t, "https://api.example.com/", endpoint.Routes[0].Url)
+}
+
+func TestDiscoverAppFallsBackToRoutes(t *testing.T) {
+	endpoint, err := multiAppConfig(t).DiscoverApp("admin")
+	helper.Ok(t, err)
+
+	helper.Assert(t, !endpoint.Internal, "DiscoverApp() found a relationship to an app without one.")
+	helper.Equals(t, "https://www.example.com/admin", endpoint.URL)
+	helper.Equals(t, 3, len(endpoint.Routes))
+	helper.Equals(t, "https://www.example.com/admin/", endpoint.Routes[0].Url)
+	helper.Equals(t, "https://admin.example.com/", endpoint.Routes[1].Url)
+	helper.Equals(t, "http://admin.example.com/", endpoint.Routes[2].Url)
+}
+
+func TestDiscoverAppErrorsForUnknownApp(t *testing.T) {
+	config := multiAppConfig(t)
+
+	_, err := config.DiscoverApp("missing")
+	helper.Assert(t, err != nil, "DiscoverApp() found an unknown app.")
+
+	_, err = config.DiscoverApp("database")
+	helper.Assert(t, err != nil, "DiscoverApp() treated a database service as an app.")
+}
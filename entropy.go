@@ -0,0 +1,92 @@
+package platformconfig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+var NoProjectEntropy = errors.New("No project entropy available to derive keys from.")
+
+// The largest key HKDF-SHA256 is able to produce (255 blocks of 32 bytes).
+const MaxDerivedKeyLength = 255 * sha256.Size
+
+// The scope a derived key is bound to.  Keys derived for the same purpose in
+// different scopes are unrelated to each other.
+const (
+	keyScopeProject     = "project"
+	keyScopeEnvironment = "environment"
+	keyScopeBranch      = "branch"
+)
+
+// Derives a stable key of the requested length (in bytes) from the project
+// entropy, labelled with a purpose such as "session" or "csrf".
+//
+// The same purpose always produces the same key on every instance and every
+// environment of the project, so no extra variables are needed to share a
+// secret between containers.  Use a distinct purpose for each use of a key.
+func (p *BuildConfig) DeriveKey(purpose string, length int) ([]byte, error) {
+	return p.deriveKey(keyScopeProject, "", purpose, length)
+}
+
+// Derives a key like DeriveKey(), but scoped to the current environment ID
+// so that each environment gets its own secret.
+func (p *RuntimeConfig) DeriveEnvironmentKey(purpose string, length int) ([]byte, error) {
+	return p.deriveKey(keyScopeEnvironment, p.environment, purpose, length)
+}
+
+// Derives a key like DeriveKey(), but scoped to the current Git branch.
+//
+// Unlike environment-scoped keys, branch-scoped keys survive an environment
+// being deleted and recreated from the same branch.
+func (p *RuntimeConfig) DeriveBranchKey(purpose string, length int) ([]byte, error) {
+	return p.deriveKey(keyScopeBranch, p.branch, purpose, length)
+}
+
+func (p *BuildConfig) deriveKey(scope string, scopeValue string, purpose string, length int) ([]byte, error) {
+	if p.projectEntropy == "" {
+		return nil, NoProjectEntropy
+	}
+	if purpose == "" {
+		return nil, errors.New("A purpose is required to derive a key.")
+	}
+
+	// The info string is length-prefixed field by field so that no
+	// combination of scope and purpose can collide with another.
+	info := make([]byte, 0, len(scope)+len(scopeValue)+len(purpose)+12)
+	for _, field := range []string{scope, scopeValue, purpose} {
+		info = append(info, byte(len(field)>>24), byte(len(field)>>16), byte(len(field)>>8), byte(len(field)))
+		info = append(info, field...)
+	}
+
+	return hkdfSha256([]byte(p.projectEntropy), []byte(p.project), info, length)
+}
+
+// An implementation of HKDF (RFC 5869) using SHA-256, extract and expand.
+func hkdfSha256(secret []byte, salt []byte, info []byte, length int) ([]byte, error) {
+	if length <= 0 || length > MaxDerivedKeyLength {
+		return nil, fmt.Errorf("Derived key length must be between 1 and %d bytes, %d requested.", MaxDerivedKeyLength, length)
+	}
+
+	if len(salt) == 0 {
+		salt = make([]byte, sha256.Size)
+	}
+	extractor := hmac.New(sha256.New, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+
+	expander := hmac.New(sha256.New, prk)
+	okm := make([]byte, 0, length+sha256.Size)
+	var block []byte
+	for counter := byte(1); len(okm) < length; counter++ {
+		expander.Reset()
+		expander.Write(block)
+		expander.Write(info)
+		expander.Write([]byte{counter})
+		block = expander.Sum(nil)
+		okm = append(okm, block...)
+	}
+
+	return okm[:length], nil
+}
@@ -0,0 +1,90 @@
+package platformconfig_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestDeriveKeyIsStable(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	key, err := config.DeriveKey("session", 32)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "659547860adb2e190e908e7dd0eb193626490000014118bde3b3ce5f738b7d8b", hex.EncodeToString(key))
+}
+
+func TestDeriveKeyHonorsLength(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	short, err := config.DeriveKey("session", 16)
+	helper.Ok(t, err)
+	long, err := config.DeriveKey("session", 100)
+	helper.Ok(t, err)
+
+	helper.Equals(t, 16, len(short))
+	helper.Equals(t, 100, len(long))
+	helper.Equals(t, short, long[:16])
+
+	_, err = config.DeriveKey("session", 0)
+	helper.Assert(t, err != nil, "DeriveKey() accepted a zero length.")
+	_, err = config.DeriveKey("session", psh.MaxDerivedKeyLength+1)
+	helper.Assert(t, err != nil, "DeriveKey() accepted an oversized length.")
+}
+
+func TestDeriveKeyDiffersByPurpose(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	session, err := config.DeriveKey("session", 32)
+	helper.Ok(t, err)
+	csrf, err := config.DeriveKey("csrf", 32)
+	helper.Ok(t, err)
+
+	helper.Assert(t, hex.EncodeToString(session) != hex.EncodeToString(csrf), "Different purposes produced the same key.")
+}
+
+func TestDeriveScopedKeys(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	env, err := config.DeriveEnvironmentKey("session", 16)
+	helper.Ok(t, err)
+	helper.Equals(t, "5c7ac6e1a5f5ecc79161a76256c03f37", hex.EncodeToString(env))
+
+	project, err := config.DeriveKey("session", 16)
+	helper.Ok(t, err)
+	branch, err := config.DeriveBranchKey("session", 16)
+	helper.Ok(t, err)
+
+	helper.Assert(t, hex.EncodeToString(project) != hex.EncodeToString(env), "Project and environment keys are the same.")
+	helper.Assert(t, hex.EncodeToString(branch) != hex.EncodeToString(env), "Branch and environment keys are the same.")
+
+	other, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_ENVIRONMENT": "master-7rqtwti",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	otherEnv, err := other.DeriveEnvironmentKey("session", 16)
+	helper.Ok(t, err)
+	otherProject, err := other.DeriveKey("session", 16)
+	helper.Ok(t, err)
+
+	helper.Assert(t, hex.EncodeToString(otherEnv) != hex.EncodeToString(env), "Environment keys do not differ between environments.")
+	helper.Equals(t, project, otherProject)
+}
+
+func TestDeriveKeyWithoutEntropyErrors(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{
+		"PLATFORM_PROJECT_ENTROPY": "",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	_, err = config.DeriveKey("session", 32)
+	helper.Equals(t, psh.NoProjectEntropy, err)
+}
@@ -0,0 +1,183 @@
+// The envexport package maps relationships and routes into conventional
+// environment variables such as DATABASE_URL or REDIS_URL, for tools spawned
+// as child processes that don't know how to read PLATFORM_RELATIONSHIPS.
+package envexport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// A Mapping tells Environ() which values to export, and under which names.
+type Mapping struct {
+	// Relationship names mapped to a variable prefix.  A relationship mapped
+	// to "DATABASE" is exported as DATABASE_URL, DATABASE_HOST, DATABASE_PORT,
+	// DATABASE_USERNAME, DATABASE_PASSWORD and DATABASE_PATH.
+	Relationships map[string]string
+
+	// Route IDs mapped to the variable their URL is exported as.
+	Routes map[string]string
+
+	// The variable the primary route's URL is exported as, if any.
+	PrimaryRoute string
+
+	// Whether to export SMTP_HOST and SMTP_PORT when email is enabled.
+	Smtp bool
+}
+
+// Returns a mapping that exports every relationship under its name in upper
+// case (so "database" becomes DATABASE_URL, etc.), leaving out relationships
+// without instances, every route with an ID as
+// ROUTE_<ID>_URL, the primary route as PRIMARY_URL, and the SMTP server.
+func DefaultMapping(config psh.RuntimeInfo) Mapping {
+	mapping := Mapping{
+		Relationships: make(map[string]string),
+		Routes:        make(map[string]string),
+		PrimaryRoute:  "PRIMARY_URL",
+		Smtp:          true,
+	}
+
+	for _, name := range config.Relationships() {
+		if _, err := config.Credentials(name); err == nil {
+			mapping.Relationships[name] = VariableName(name)
+		}
+	}
+
+	for _, route := range config.Routes() {
+		if route.Id != "" {
+			mapping.Routes[route.Id] = "ROUTE_" + VariableName(route.Id) + "_URL"
+		}
+	}
+
+	return mapping
+}
+
+// Turns a relationship name or route ID into an environment variable name:
+// upper case, with anything but letters and digits replaced by underscores.
+func VariableName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// Returns the variables described by the mapping as a sorted list of
+// "KEY=value" strings, in the format of os.Environ().  It is an error for the
+// mapping to name a relationship or route that doesn't exist.
+func Environ(config psh.RuntimeInfo, mapping Mapping) ([]string, error) {
+	vars := make(map[string]string)
+
+	for name, prefix := range mapping.Relationships {
+		creds, err := config.Credentials(name)
+		if err != nil {
+			return nil, err
+		}
+
+		vars[prefix+"_URL"] = URL(creds)
+		vars[prefix+"_HOST"] = creds.Host
+		vars[prefix+"_PORT"] = strconv.Itoa(creds.Port)
+		vars[prefix+"_USERNAME"] = creds.Username
+		vars[prefix+"_PASSWORD"] = creds.Password
+		vars[prefix+"_PATH"] = creds.Path
+	}
+
+	for id, key := range mapping.Routes {
+		route, ok := config.Route(id)
+		if !ok {
+			return nil, fmt.Errorf("No such route: %s", id)
+		}
+		vars[key] = route.Url
+	}
+
+	if mapping.PrimaryRoute != "" {
+		if route, ok := config.PrimaryRoute(); ok {
+			vars[mapping.PrimaryRoute] = route.Url
+		}
+	}
+
+	if mapping.Smtp && config.SmtpHost() != "" {
+		vars["SMTP_HOST"] = config.SmtpHost()
+		vars["SMTP_PORT"] = "25"
+	}
+
+	environ := make([]string, 0, len(vars))
+	for key, val := range vars {
+		environ = append(environ, key+"="+val)
+	}
+	sort.Strings(environ)
+
+	return environ, nil
+}
+
+// Returns an *exec.Cmd that runs a program with the current process
+// environment plus the variables described by the mapping.
+func Command(ctx context.Context, config psh.RuntimeInfo, mapping Mapping, name string, args ...string) (*exec.Cmd, error) {
+	environ, err := Environ(config, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), environ...)
+
+	return cmd, nil
+}
+
+// The URL schemes used for each relationship scheme, where the conventional
+// URL scheme differs from the one Platform.sh reports.
+var urlSchemes = map[string]string{
+	"pgsql": "postgresql",
+	"solr":  "http",
+}
+
+// Formats a credential as a URL, following the usual convention for its
+// service: mysql://, postgresql://, redis://, amqp://, etc.  The username and
+// password are escaped.
+func URL(creds psh.Credential) string {
+	scheme := creds.Scheme
+	if mapped, ok := urlSchemes[scheme]; ok {
+		scheme = mapped
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   creds.Host,
+	}
+	if creds.Port != 0 {
+		u.Host = net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port))
+	}
+
+	switch {
+	case creds.Username != "" && creds.Password != "":
+		u.User = url.UserPassword(creds.Username, creds.Password)
+	case creds.Username != "":
+		u.User = url.User(creds.Username)
+	case creds.Password != "":
+		// Redis and similar services authenticate with a password only.
+		u.User = url.UserPassword("", creds.Password)
+	}
+
+	switch {
+	case creds.Path != "":
+		u.Path = "/" + strings.TrimPrefix(creds.Path, "/")
+	case scheme == "amqp" || scheme == "amqps":
+		// The default vhost.
+		u.Path = "/"
+	}
+
+	return u.String()
+}
@@ -0,0 +1,347 @@
+// The fake package provides an in-memory implementation of the config reader
+// interfaces, for testing code that depends on them without crafting
+// Platform.sh environment variables.
+//
+//	config := fake.New().
+//		WithBranch("main").
+//		WithEnvironmentType(psh.EnvironmentProduction).
+//		WithCredential("database", psh.Credential{Host: "localhost", Port: 3306})
+package fake
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// An in-memory config implementing psh.BuildInfo and psh.RuntimeInfo.
+//
+// The With* methods modify the config and return it, so that calls can be
+// chained.
+type Config struct {
+	applicationName string
+	treeId          string
+	appDir          string
+	project         string
+	projectEntropy  string
+	variables       psh.EnvList
+
+	branch       string
+	environment  string
+	envType      string
+	documentRoot string
+	smtpHost     string
+	port         string
+	socket       string
+	dedicated    bool
+
+	credentials psh.Credentials
+	routes      psh.Routes
+}
+
+var (
+	_ psh.BuildInfo   = (*Config)(nil)
+	_ psh.RuntimeInfo = (*Config)(nil)
+)
+
+// Returns a fake config for an application named "app" on a development
+// branch, with no relationships, routes or variables.
+func New() *Config {
+	return &Config{
+		applicationName: "app",
+		appDir:          "/app",
+		branch:          "feature",
+		environment:     "feature",
+		documentRoot:    "/app/web",
+		port:            "8888",
+		variables:       make(psh.EnvList),
+		credentials:     make(psh.Credentials),
+		routes:          make(psh.Routes),
+	}
+}
+
+// Sets the application name.
+func (c *Config) WithApplicationName(name string) *Config {
+	c.applicationName = name
+	return c
+}
+
+// Sets the tree ID.
+func (c *Config) WithTreeId(treeId string) *Config {
+	c.treeId = treeId
+	return c
+}
+
+// Sets the application directory.
+func (c *Config) WithAppDir(appDir string) *Config {
+	c.appDir = appDir
+	return c
+}
+
+// Sets the project ID.
+func (c *Config) WithProject(project string) *Config {
+	c.project = project
+	return c
+}
+
+// Sets the project entropy.
+func (c *Config) WithProjectEntropy(entropy string) *Config {
+	c.projectEntropy = entropy
+	return c
+}
+
+// Sets a variable.
+func (c *Config) WithVariable(name string, value string) *Config {
+	c.variables[name] = value
+	return c
+}
+
+// Sets the branch, and the environment ID to the same value.  Use
+// WithEnvironment() afterwards for a different environment ID.
+func (c *Config) WithBranch(branch string) *Config {
+	c.branch = branch
+	c.environment = branch
+	return c
+}
+
+// Sets the environment ID.
+func (c *Config) WithEnvironment(environment string) *Config {
+	c.environment = environment
+	return c
+}
+
+// Sets the environment type, one of psh.EnvironmentProduction,
+// psh.EnvironmentStaging or psh.EnvironmentDevelopment.
+func (c *Config) WithEnvironmentType(envType string) *Config {
+	c.envType = envType
+	return c
+}
+
+// Sets the document root.
+func (c *Config) WithDocumentRoot(documentRoot string) *Config {
+	c.documentRoot = documentRoot
+	return c
+}
+
+// Sets the SMTP host.  An empty host disables email.
+func (c *Config) WithSmtpHost(host string) *Config {
+	c.smtpHost = host
+	return c
+}
+
+// Sets the port the application listens on.
+func (c *Config) WithPort(port string) *Config {
+	c.port = port
+	return c
+}
+
+// Sets the socket the application listens on.
+func (c *Config) WithSocket(socket string) *Config {
+	c.socket = socket
+	return c
+}
+
+// Marks the environment as a Dedicated one.
+func (c *Config) WithDedicated() *Config {
+	c.dedicated = true
+	return c
+}
+
+// Adds an instance to a relationship.
+func (c *Config) WithCredential(relationship string, creds psh.Credential) *Config {
+	c.credentials[relationship] = append(c.credentials[relationship], creds)
+	return c
+}
+
+// Adds a route.  The route's Url is set from url.
+func (c *Config) WithRoute(url string, route psh.Route) *Config {
+	route.Url = url
+	c.routes[url] = &route
+	return c
+}
+
+// See psh.BuildConfig.ApplicationName().
+func (c *Config) ApplicationName() string {
+	return c.applicationName
+}
+
+// See psh.BuildConfig.TreeId().
+func (c *Config) TreeId() string {
+	return c.treeId
+}
+
+// See psh.BuildConfig.AppDir().
+func (c *Config) AppDir() string {
+	return c.appDir
+}
+
+// See psh.BuildConfig.Project().
+func (c *Config) Project() string {
+	return c.project
+}
+
+// See psh.BuildConfig.ProjectEntropy().
+func (c *Config) ProjectEntropy() string {
+	return c.projectEntropy
+}
+
+// See psh.BuildConfig.Variable().
+func (c *Config) Variable(name string, defaultValue string) string {
+	if val, ok := c.variables[name]; ok {
+		return val
+	}
+	return defaultValue
+}
+
+// See psh.BuildConfig.Variables().
+func (c *Config) Variables() psh.EnvList {
+	return c.variables
+}
+
+// See psh.RuntimeConfig.Branch().
+func (c *Config) Branch() string {
+	return c.branch
+}
+
+// See psh.RuntimeConfig.Environment().
+func (c *Config) Environment() string {
+	return c.environment
+}
+
+// See psh.RuntimeConfig.DocumentRoot().
+func (c *Config) DocumentRoot() string {
+	return c.documentRoot
+}
+
+// See psh.RuntimeConfig.SmtpHost().
+func (c *Config) SmtpHost() string {
+	return c.smtpHost
+}
+
+// See psh.RuntimeConfig.EmailEnabled().
+func (c *Config) EmailEnabled() bool {
+	return c.smtpHost != ""
+}
+
+// See psh.RuntimeConfig.Port().
+func (c *Config) Port() string {
+	return c.port
+}
+
+// See psh.RuntimeConfig.Socket().
+func (c *Config) Socket() string {
+	return c.socket
+}
+
+// See psh.RuntimeConfig.OnDedicated().
+func (c *Config) OnDedicated() bool {
+	return c.dedicated
+}
+
+// See psh.RuntimeConfig.OnProduction().
+func (c *Config) OnProduction() bool {
+	return c.EnvironmentType() == psh.EnvironmentProduction
+}
+
+// See psh.RuntimeConfig.OnStaging().
+func (c *Config) OnStaging() bool {
+	return c.EnvironmentType() == psh.EnvironmentStaging
+}
+
+// See psh.RuntimeConfig.OnDevelopment().
+func (c *Config) OnDevelopment() bool {
+	return c.EnvironmentType() == psh.EnvironmentDevelopment
+}
+
+// Returns the type set with WithEnvironmentType(), "development" by default.
+func (c *Config) EnvironmentType() string {
+	if c.envType == "" {
+		return psh.EnvironmentDevelopment
+	}
+	return c.envType
+}
+
+// See psh.RuntimeConfig.Credentials().
+func (c *Config) Credentials(relationship string) (psh.Credential, error) {
+	if creds, ok := c.credentials[relationship]; ok && len(creds) > 0 {
+		return creds[0], nil
+	}
+
+	return psh.Credential{}, fmt.Errorf("No such relationship: %s", relationship)
+}
+
+// See psh.RuntimeConfig.AllCredentials().
+func (c *Config) AllCredentials(relationship string) ([]psh.Credential, error) {
+	if creds, ok := c.credentials[relationship]; ok && len(creds) > 0 {
+		return append([]psh.Credential(nil), creds...), nil
+	}
+
+	return nil, fmt.Errorf("No such relationship: %s", relationship)
+}
+
+// See psh.RuntimeConfig.Relationships().
+func (c *Config) Relationships() []string {
+	names := make([]string, 0, len(c.credentials))
+	for name := range c.credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// See psh.RuntimeConfig.Routes().
+func (c *Config) Routes() psh.Routes {
+	return c.routes
+}
+
+// See psh.RuntimeConfig.Route().
+func (c *Config) Route(id string) (psh.Route, bool) {
+	for _, route := range c.routes {
+		if route.Id == id {
+			return *route, true
+		}
+	}
+
+	return psh.Route{}, false
+}
+
+// See psh.RuntimeConfig.PrimaryRoute().
+func (c *Config) PrimaryRoute() (psh.Route, bool) {
+	for _, route := range c.routes {
+		if route.Primary {
+			return *route, true
+		}
+	}
+
+	return psh.Route{}, false
+}
+
+// See psh.RuntimeConfig.UpstreamRoutes().
+func (c *Config) UpstreamRoutes() psh.Routes {
+	ret := make(psh.Routes)
+
+	for url, route := range c.routes {
+		if route.Type == "upstream" {
+			ret[url] = route
+		}
+	}
+
+	return ret
+}
+
+// See psh.RuntimeConfig.UpstreamRoutesForApp().
+func (c *Config) UpstreamRoutesForApp(appName string) psh.Routes {
+	ret := make(psh.Routes)
+
+	for url, route := range c.UpstreamRoutes() {
+		parts := strings.Split(route.Upstream, ":")
+		if appName == parts[0] {
+			ret[url] = route
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,73 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	fake "github.com/platformsh/config-reader-go/v2/fake"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+// A function under test that only depends on the interfaces.
+func databaseHost(config psh.RuntimeInfo) string {
+	if !config.OnProduction() {
+		return "localhost"
+	}
+	creds, err := config.Credentials("database")
+	if err != nil {
+		return ""
+	}
+	return creds.Host
+}
+
+func TestFakeDefaults(t *testing.T) {
+	config := fake.New()
+
+	helper.Equals(t, "app", config.ApplicationName())
+	helper.Equals(t, psh.EnvironmentDevelopment, config.EnvironmentType())
+	helper.Assert(t, config.OnDevelopment(), "OnDevelopment() returned false by default.")
+	helper.Equals(t, "default", config.Variable("missing", "default"))
+
+	_, err := config.Credentials("database")
+	helper.Assert(t, err != nil, "Credentials() returned a relationship that doesn't exist.")
+}
+
+func TestFakeBuilder(t *testing.T) {
+	config := fake.New().
+		WithBranch("main").
+		WithEnvironmentType(psh.EnvironmentProduction).
+		WithVariable("somevar", "someval").
+		WithCredential("database", psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306}).
+		WithRoute("https://example.com/", psh.Route{Type: "upstream", Upstream: "app:http", Primary: true, Id: "main"}).
+		WithRoute("https://api.example.com/", psh.Route{Type: "upstream", Upstream: "api:http"}).
+		WithRoute("http://example.com/", psh.Route{Type: "redirect"})
+
+	helper.Equals(t, "main", config.Branch())
+	helper.Equals(t, "main", config.Environment())
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false when it should be true.")
+	helper.Equals(t, "someval", config.Variable("somevar", ""))
+	helper.Equals(t, "database.internal", databaseHost(config))
+
+	route, ok := config.PrimaryRoute()
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "https://example.com/", route.Url)
+
+	route, ok = config.Route("main")
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "app:http", route.Upstream)
+
+	helper.Equals(t, 3, len(config.Routes()))
+	helper.Equals(t, 2, len(config.UpstreamRoutes()))
+	helper.Equals(t, 1, len(config.UpstreamRoutesForApp("api")))
+}
+
+func TestFakeCanBeCarriedInContext(t *testing.T) {
+	config := fake.New().WithDedicated()
+
+	ctx := psh.NewRuntimeInfoContext(context.Background(), config)
+
+	info, ok := psh.RuntimeInfoFromContext(ctx)
+	helper.Equals(t, true, ok)
+	helper.Assert(t, info.OnDedicated(), "The fake config was not found in the context.")
+}
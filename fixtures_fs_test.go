@@ -0,0 +1,26 @@
+//go:build go1.16
+// +build go1.16
+
+package platformconfig_test
+
+import (
+	"os"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestRuntimeEnvFSMatchesRuntimeEnv(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnvFS(t, os.DirFS("testdata"), psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	expected, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, expected, config)
+
+	build, err := psh.NewBuildConfigReal(helper.BuildEnvFS(t, os.DirFS("testdata"), psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+	helper.Equals(t, psh.PhaseBuild, build.Phase())
+}
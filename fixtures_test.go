@@ -0,0 +1,157 @@
+package platformconfig_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestEnvBuilderProducesRuntimeConfig(t *testing.T) {
+	env := helper.NewEnvBuilder().
+		WithBranch("master").
+		WithRelationship("redis", psh.Credential{Scheme: "redis", Host: "redis.internal", Port: 6379}).
+		WithRoute("https://example.com/", psh.Route{Type: "upstream", Upstream: "app:http", Primary: true, Id: "main"}).
+		WithVariable("somevar", "someval").
+		WithApplication(map[string]interface{}{"name": "api", "type": "golang:1.14"})
+
+	config, err := psh.NewRuntimeConfigReal(env.Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	creds, err := config.Credentials("redis")
+	helper.Ok(t, err)
+	helper.Equals(t, "redis.internal", creds.Host)
+	helper.Equals(t, 6379, creds.Port)
+
+	route, ok := config.PrimaryRoute()
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "https://example.com/", route.Url)
+	helper.Equals(t, "app:http", route.Upstream)
+
+	helper.Equals(t, "master", config.Branch())
+	helper.Equals(t, "someval", config.Variable("somevar", ""))
+	helper.Equals(t, "api", config.ApplicationName())
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false on master.")
+}
+
+func TestEnvBuilderOnDedicated(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().OnDedicated().WithBranch("production").Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Assert(t, config.OnDedicated(), "OnDedicated() returned false on Dedicated.")
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false on the production branch.")
+}
+
+func TestEnvBuilderBuildHasNoRuntimeValues(t *testing.T) {
+	env := helper.NewEnvBuilder().WithVariable("somevar", "someval")
+
+	_, err := psh.NewRuntimeConfigReal(env.Build(), "PLATFORM_")
+	helper.Equals(t, psh.NotRuntimePlatform, err)
+
+	config, err := psh.NewBuildConfigReal(env.Build(), "PLATFORM_")
+	helper.Ok(t, err)
+	helper.Equals(t, "someval", config.Variable("somevar", ""))
+	helper.Equals(t, psh.PhaseBuild, config.Phase())
+}
+
+func TestEnvBuilderWithEnvOverrides(t *testing.T) {
+	env := helper.NewEnvBuilder().WithEnv("PORT", "9000").WithEnv("PLATFORM_ENVIRONMENT_TYPE", "staging")
+
+	helper.Equals(t, "9000", env.EnvList()["PORT"])
+
+	config, err := psh.NewRuntimeConfigReal(env.Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+	helper.Equals(t, "9000", config.Port())
+	helper.Assert(t, config.OnStaging(), "OnStaging() returned false.")
+}
+
+// Records fatal errors instead of stopping the test, to check that the
+// fixture helpers fail loudly.
+type fatalRecorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *fatalRecorder) Helper() {}
+
+func (r *fatalRecorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRuntimeEnvFromDirMatchesRuntimeEnv(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnvFromDir(t, "testdata", psh.EnvList{
+		"PLATFORM_BRANCH": "master",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	expected, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_BRANCH": "master",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, expected, config)
+}
+
+func TestBuildEnvFromDirHasNoRuntimeValues(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnvFromDir(t, "testdata", psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseBuild, config.Phase())
+	helper.Equals(t, "someval", config.Variable("somevar", ""))
+}
+
+func TestFixtureHelpersFailOnBrokenFixtures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-reader-go")
+	helper.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	recorder := &fatalRecorder{TB: t}
+	helper.RuntimeEnvFromDir(recorder, dir, psh.EnvList{})
+	helper.Equals(t, 1, len(recorder.failures))
+
+	helper.Ok(t, ioutil.WriteFile(filepath.Join(dir, "ENV.json"), []byte(`{"PLATFORM_APPLICATION_NAME": "app"}`), 0644))
+	helper.Ok(t, ioutil.WriteFile(filepath.Join(dir, "ENV_runtime.json"), []byte(`{"PLATFORM_BRANCH": "main"}`), 0644))
+	helper.Ok(t, ioutil.WriteFile(filepath.Join(dir, "PLATFORM_ROUTES.json"), []byte(`{"broken": `), 0644))
+
+	recorder = &fatalRecorder{TB: t}
+	helper.RuntimeEnvFromDir(recorder, dir, psh.EnvList{})
+	helper.Equals(t, 1, len(recorder.failures))
+
+	// Routes are not part of the build environment.
+	recorder = &fatalRecorder{TB: t}
+	helper.BuildEnvFromDir(recorder, dir, psh.EnvList{})
+	helper.Equals(t, 0, len(recorder.failures))
+
+	recorder = &fatalRecorder{TB: t}
+	helper.LoadJsonFileT(recorder, filepath.Join(dir, "missing.json"))
+	helper.EncodeJsonFileT(recorder, filepath.Join(dir, "PLATFORM_ROUTES.json"))
+	helper.Equals(t, 2, len(recorder.failures))
+}
+
+func TestServiceFixturesLoad(t *testing.T) {
+	fixtures := helper.ServiceFixtures(t)
+
+	for _, service := range []string{"redis", "kafka", "rabbitmq", "solr", "opensearch", "influxdb", "network-storage", "varnish", "chrome-headless", "vault-kms", "clickhouse", "mariadb"} {
+		helper.Assert(t, len(fixtures[service]) > 0, "Missing service fixture for %s.", service)
+	}
+
+	for service, versions := range fixtures {
+		for _, version := range versions {
+			rels := helper.ServiceFixture(t, service, version)
+
+			config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().WithServiceFixture(t, service, version).Runtime(), "PLATFORM_")
+			helper.Ok(t, err)
+
+			for name := range rels {
+				creds, err := config.Credentials(name)
+				helper.Ok(t, err)
+				helper.Equals(t, service+":"+version, creds.Type)
+				helper.Assert(t, creds.Host != "", "The %s fixture of %s %s has no host.", name, service, version)
+			}
+		}
+	}
+}
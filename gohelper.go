@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 )
 
@@ -17,6 +18,13 @@ var NotValidPlatform = errors.New("No valid platform found.")
 
 var NotRuntimePlatform = errors.New("No valid runtime platform found.")
 
+// The possible values of EnvironmentType().
+const (
+	EnvironmentProduction  = "production"
+	EnvironmentStaging     = "staging"
+	EnvironmentDevelopment = "development"
+)
+
 type EnvList map[string]string
 
 type envReader func(string) string
@@ -38,7 +46,11 @@ type Credential struct {
 	Hostname string `json:"hostname"`
 	Query    struct {
 		IsMaster bool `json:"is_master"`
-	}
+	} `json:"query"`
+
+	// What the JSON of the relationship holds beyond the fields above, if
+	// anything.  Nil for credentials built in code.
+	Raw *CredentialRaw `json:"-"`
 }
 
 type Credentials map[string][]Credential
@@ -94,6 +106,16 @@ type BuildConfig struct {
 	variables   EnvList
 	application map[string]interface{}
 
+	// Typed sections of the application definition.
+	workers map[string]Worker
+	crons   map[string]Cron
+	worker  *Worker
+	mounts  []Mount
+	hooks   Hooks
+
+	// The lifecycle phase, detected at construction.
+	phase string
+
 	// Internal data.
 	varPrefix string
 }
@@ -107,6 +129,7 @@ type RuntimeConfig struct {
 	documentRoot string
 	smtpHost     string
 	mode         string
+	envType      string
 
 	// Prefixed complex values.
 	credentials Credentials
@@ -116,6 +139,9 @@ type RuntimeConfig struct {
 	// Unprefixed simple values.
 	socket string
 	port   string
+
+	// Overrides set by the caller.
+	productionBranches []string
 }
 
 func NewBuildConfigReal(getter envReader, varPrefix string) (*BuildConfig, error) {
@@ -160,8 +186,14 @@ func NewBuildConfigReal(getter envReader, varPrefix string) (*BuildConfig, error
 			return nil, err
 		}
 		p.application = parsedApplication
+
+		if err := p.extractApplication(); err != nil {
+			return nil, err
+		}
 	}
 
+	p.phase = detectPhase(getter, p.varPrefix, p.worker)
+
 	return p, nil
 }
 
@@ -188,6 +220,7 @@ func NewRuntimeConfigReal(getter envReader, varPrefix string) (*RuntimeConfig, e
 	p.project = getter(p.varPrefix + "PROJECT")
 	p.smtpHost = getter(p.varPrefix + "SMTP_HOST")
 	p.mode = getter(p.varPrefix + "MODE")
+	p.envType = getter(p.varPrefix + "ENVIRONMENT_TYPE")
 	p.socket = getter("SOCKET")
 	p.port = getter("PORT")
 
@@ -258,18 +291,71 @@ func (p *RuntimeConfig) OnDedicated() bool {
 
 // Determines if the current environment is a production environment.
 //
-// Note: There may be a few edge cases where this is not entirely correct on Dedicated,
-// if the production branch is not named `production`.  In that case you'll need to use
-// your own logic.
+// See EnvironmentType() for how the environment type is determined.
 func (p *RuntimeConfig) OnProduction() bool {
-	var prodBranch string
+	return p.EnvironmentType() == EnvironmentProduction
+}
+
+// Determines if the current environment is a staging environment.
+//
+// See EnvironmentType() for how the environment type is determined.
+func (p *RuntimeConfig) OnStaging() bool {
+	return p.EnvironmentType() == EnvironmentStaging
+}
+
+// Determines if the current environment is a development environment.
+//
+// See EnvironmentType() for how the environment type is determined.
+func (p *RuntimeConfig) OnDevelopment() bool {
+	return p.EnvironmentType() == EnvironmentDevelopment
+}
+
+// The type of the current environment: "production", "staging" or "development".
+//
+// If the platform provides PLATFORM_ENVIRONMENT_TYPE its value is authoritative.
+// Otherwise the type is guessed from the branch name: the production branches
+// (see SetProductionBranches()) are production, a `staging` branch on Dedicated
+// is staging, and everything else is development.
+func (p *RuntimeConfig) EnvironmentType() string {
+	if p.envType != "" {
+		return p.envType
+	}
+
+	for _, branch := range p.ProductionBranches() {
+		if p.branch == branch {
+			return EnvironmentProduction
+		}
+	}
+
+	if p.OnDedicated() && p.branch == "staging" {
+		return EnvironmentStaging
+	}
+
+	return EnvironmentDevelopment
+}
+
+// The branch names treated as production when the platform does not provide
+// PLATFORM_ENVIRONMENT_TYPE.
+//
+// Unless overridden with SetProductionBranches() these are `production` on
+// Dedicated, and `master` or `main` otherwise.
+func (p *RuntimeConfig) ProductionBranches() []string {
+	if len(p.productionBranches) > 0 {
+		return p.productionBranches
+	}
+
 	if p.OnDedicated() {
-		prodBranch = "production"
-	} else {
-		prodBranch = "master"
+		return []string{"production"}
 	}
 
-	return p.branch == prodBranch
+	return []string{"master", "main"}
+}
+
+// Overrides the branch names treated as production when the platform does not
+// provide PLATFORM_ENVIRONMENT_TYPE.  Calling it with no branches restores the
+// default guess.
+func (p *RuntimeConfig) SetProductionBranches(branches ...string) {
+	p.productionBranches = branches
 }
 
 // The name of the application, as defined in its configuration.
@@ -295,6 +381,8 @@ func (p *BuildConfig) Project() string {
 }
 
 // A random string generated for each project, useful for generating hash keys.
+//
+// See DeriveKey() for deriving purpose-specific secrets from it.
 func (p *BuildConfig) ProjectEntropy() string {
 	return p.projectEntropy
 }
@@ -320,6 +408,13 @@ func (p *RuntimeConfig) SmtpHost() string {
 	return p.smtpHost
 }
 
+// Determines whether outgoing email is enabled on the environment.  When it
+// is, the SMTP server at SmtpHost() accepts mail on port 25 without
+// authentication.
+func (p *RuntimeConfig) EmailEnabled() bool {
+	return p.smtpHost != ""
+}
+
 // The TCP port number the application should listen to for incoming requests.
 func (p *RuntimeConfig) Port() string {
 	return p.port
@@ -356,13 +451,34 @@ func (p *RuntimeConfig) Credentials(relationship string) (Credential, error) {
 	// Non-zero relationship indexes are not currently used, so hard code 0 for now.
 	// On the off chance that ever changes, we'll add another method that allows
 	// callers to specify an offset.
-	if creds, ok := p.credentials[relationship]; ok {
+	if creds, ok := p.credentials[relationship]; ok && len(creds) > 0 {
 		return creds[0], nil
 	}
 
 	return Credential{}, fmt.Errorf("No such relationship: %s", relationship)
 }
 
+// Retrieves the credentials of every instance of a relationship, such as the
+// members of a replica set.  The first one is the one Credentials() returns.
+func (p *RuntimeConfig) AllCredentials(relationship string) ([]Credential, error) {
+	if creds, ok := p.credentials[relationship]; ok && len(creds) > 0 {
+		return append([]Credential(nil), creds...), nil
+	}
+
+	return nil, fmt.Errorf("No such relationship: %s", relationship)
+}
+
+// Returns the names of all relationships, sorted.
+func (p *RuntimeConfig) Relationships() []string {
+	names := make([]string, 0, len(p.credentials))
+	for name := range p.credentials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Returns the routes definition.
 // This is an slice of Route structs.
 func (p *RuntimeConfig) Routes() Routes {
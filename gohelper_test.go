@@ -113,6 +113,16 @@ func TestBuildAndRuntimePropertyInRuntimeExists(t *testing.T) {
 	helper.Equals(t, "unix://tmp/blah.sock", config.Socket())
 }
 
+func TestEmailEnabledFollowsSmtpHost(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+	helper.Assert(t, config.EmailEnabled(), "EmailEnabled() returned false with an SMTP host.")
+
+	config, err = psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{"PLATFORM_SMTP_HOST": ""}), "PLATFORM_")
+	helper.Ok(t, err)
+	helper.Assert(t, !config.EmailEnabled(), "EmailEnabled() returned true without an SMTP host.")
+}
+
 func TestReadingExistingVariableWorks(t *testing.T) {
 	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
 	helper.Ok(t, err)
@@ -244,3 +254,94 @@ func TestLocalNoRelationships(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestOnProductionOnStandardMainReturnsTrue(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_BRANCH": "main",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false when it should be true.")
+}
+
+func TestEnvironmentTypeIsAuthoritative(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_BRANCH":           "master",
+		"PLATFORM_ENVIRONMENT_TYPE": "staging",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.EnvironmentStaging, config.EnvironmentType())
+	helper.Assert(t, config.OnStaging(), "OnStaging() returned false when it should be true.")
+	helper.Assert(t, !config.OnProduction(), "OnProduction() returned true when it should be false.")
+	helper.Assert(t, !config.OnDevelopment(), "OnDevelopment() returned true when it should be false.")
+}
+
+func TestEnvironmentTypeProductionOnAnyBranch(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_BRANCH":           "live",
+		"PLATFORM_ENVIRONMENT_TYPE": "production",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false when it should be true.")
+}
+
+func TestEnvironmentTypeGuessedOnDedicatedStaging(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_MODE":   "enterprise",
+		"PLATFORM_BRANCH": "staging",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.EnvironmentStaging, config.EnvironmentType())
+}
+
+func TestEnvironmentTypeDefaultsToDevelopment(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.EnvironmentDevelopment, config.EnvironmentType())
+	helper.Assert(t, config.OnDevelopment(), "OnDevelopment() returned false when it should be true.")
+}
+
+func TestProductionBranchOverride(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_BRANCH": "live",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Assert(t, !config.OnProduction(), "OnProduction() returned true when it should be false.")
+
+	config.SetProductionBranches("live")
+	helper.Equals(t, []string{"live"}, config.ProductionBranches())
+	helper.Assert(t, config.OnProduction(), "OnProduction() returned false when it should be true.")
+
+	config.SetProductionBranches()
+	helper.Equals(t, []string{"master", "main"}, config.ProductionBranches())
+}
+
+func TestAllCredentialsReturnsEveryInstance(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().
+		WithRelationship("mongodb",
+			psh.Credential{Host: "mongodb1.internal", Port: 27017},
+			psh.Credential{Host: "mongodb2.internal", Port: 27017}).
+		Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	creds, err := config.AllCredentials("mongodb")
+	helper.Ok(t, err)
+	helper.Equals(t, 2, len(creds))
+	helper.Equals(t, "mongodb1.internal", creds[0].Host)
+	helper.Equals(t, "mongodb2.internal", creds[1].Host)
+
+	_, err = config.AllCredentials("missing")
+	helper.Assert(t, err != nil, "AllCredentials() returned no error for a missing relationship.")
+}
+
+func TestRelationshipsListsNames(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, []string{"database", "elasticsearch", "memcached", "mongodb", "postgresql", "rabbitmq", "solr"}, config.Relationships())
+}
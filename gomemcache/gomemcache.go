@@ -1,12 +1,62 @@
 package gomemcache
 
 import (
-  "fmt"
-  psh "github.com/platformsh/config-reader-go/v2"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
 )
 
 // The gomemcache library requires a specific string to connect to Memcached.
 func FormattedCredentials(creds psh.Credential) (string, error) {
-  formatted := fmt.Sprintf("%s:%d", creds.Host, creds.Port)
-  return formatted, nil
+	formatted := fmt.Sprintf("%s:%d", creds.Host, creds.Port)
+	return formatted, nil
+}
+
+// Returns the host:port address of every instance of the given relationships,
+// for gomemcache.New(servers...).
+//
+// gomemcache picks the server for a key by its position in the list, so the
+// addresses are sorted and deduplicated: every instance of the application
+// gets the same list, and sends each key to the same server.
+func Servers(config psh.CredentialProvider, relationships ...string) ([]string, error) {
+	seen := make(map[string]bool)
+	servers := make([]string, 0)
+
+	for _, relationship := range relationships {
+		creds, err := config.AllCredentials(relationship)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, instance := range creds {
+			if err := Validate(instance); err != nil {
+				return nil, fmt.Errorf("%s: %s", relationship, err)
+			}
+
+			server := net.JoinHostPort(instance.Host, strconv.Itoa(instance.Port))
+			if !seen[server] {
+				seen[server] = true
+				servers = append(servers, server)
+			}
+		}
+	}
+	sort.Strings(servers)
+
+	return servers, nil
+}
+
+// Checks that a relationship points to a Memcached service.
+func Validate(creds psh.Credential) error {
+	if creds.Scheme != "memcached" && !strings.HasPrefix(creds.Type, "memcached:") {
+		return fmt.Errorf("Not a Memcached relationship: %s", creds.Type)
+	}
+	if creds.Host == "" || creds.Port == 0 {
+		return fmt.Errorf("The Memcached relationship has no host or port")
+	}
+
+	return nil
 }
@@ -19,3 +19,56 @@ func TestGoMemcacheFormatterCalled(t *testing.T) {
 
 	helper.Equals(t, "memcached.internal:11211", formatted)
 }
+
+func TestGoMemcacheFormatterWithServiceFixture(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().WithServiceFixture(t, "memcached", "1.6").Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("memcached")
+	helper.Ok(t, err)
+
+	formatted, err := mem.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "memcached.internal:11211", formatted)
+}
+
+func TestGoMemcacheServersAcrossRelationships(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().
+		WithRelationship("sessions",
+			psh.Credential{Scheme: "memcached", Host: "sessions2.internal", Port: 11211},
+			psh.Credential{Scheme: "memcached", Host: "sessions1.internal", Port: 11211}).
+		WithRelationship("cache",
+			psh.Credential{Scheme: "memcached", Host: "cache.internal", Port: 11211},
+			psh.Credential{Scheme: "memcached", Host: "sessions1.internal", Port: 11211}).
+		WithRelationship("database", psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306}).
+		Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	servers, err := mem.Servers(config, "sessions", "cache")
+	helper.Ok(t, err)
+	helper.Equals(t, []string{"cache.internal:11211", "sessions1.internal:11211", "sessions2.internal:11211"}, servers)
+
+	servers, err = mem.Servers(config, "cache", "sessions")
+	helper.Ok(t, err)
+	helper.Equals(t, []string{"cache.internal:11211", "sessions1.internal:11211", "sessions2.internal:11211"}, servers)
+
+	_, err = mem.Servers(config, "cache", "database")
+	helper.Assert(t, err != nil, "Servers() accepted a MySQL relationship.")
+
+	_, err = mem.Servers(config, "missing")
+	helper.Assert(t, err != nil, "Servers() accepted a missing relationship.")
+}
+
+func TestGoMemcacheValidate(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("memcached")
+	helper.Ok(t, err)
+	helper.Ok(t, mem.Validate(credentials))
+
+	credentials, err = config.Credentials("database")
+	helper.Ok(t, err)
+	helper.Assert(t, mem.Validate(credentials) != nil, "Validate() accepted a MySQL relationship.")
+}
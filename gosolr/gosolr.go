@@ -1,15 +1,96 @@
 package gosolr
 
 import (
-  "fmt"
-  psh "github.com/platformsh/config-reader-go/v2"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
 )
 
 // Go-solr requires a string that includes the full collection path to  connect to Solr.
 func FormattedCredentials(creds psh.Credential) (string, error) {
+	base, err := BaseURL(creds)
+	if err != nil {
+		return "", err
+	}
+
+	if core := Core(creds); core != "" {
+		return base + "/" + core, nil
+	}
+
+	return base, nil
+}
+
+// Returns the URL of the Solr server, without the core, such as
+// "http://solr.internal:8080/solr".
+//
+// The "solr" scheme of Platform.sh relationships means plain HTTP; "http" and
+// "https" are used as they are.
+func BaseURL(creds psh.Credential) (string, error) {
+	if creds.Host == "" {
+		return "", fmt.Errorf("The Solr relationship has no host")
+	}
+
+	scheme := creds.Scheme
+	switch scheme {
+	case "", "solr":
+		scheme = "http"
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("Not a Solr relationship: %s", creds.Scheme)
+	}
+
+	base := scheme + "://" + net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port))
+	if dir := path.Dir(strings.Trim(creds.Path, "/")); dir != "." {
+		base += "/" + dir
+	}
+
+	return base, nil
+}
+
+// Returns the name of the core (or collection) the relationship points to,
+// the last part of its path.
+func Core(creds psh.Credential) string {
+	trimmed := strings.Trim(creds.Path, "/")
+	if trimmed == "" {
+		return ""
+	}
+
+	return path.Base(trimmed)
+}
+
+// Returns the URL of every endpoint of a Solr service the application has a
+// relationship to, keyed by endpoint name.  A multi-core service exposes one
+// endpoint per core:
+//
+//	relationships:
+//	    solrmain: "solr:main"
+//	    solrextra: "solr:extra"
+//
+// gives {"main": ".../solr/maincore", "extra": ".../solr/extracore"}.
+func Endpoints(config psh.CredentialProvider, service string) (map[string]string, error) {
+	endpoints := make(map[string]string)
+
+	for _, relationship := range config.Relationships() {
+		// Relationships without instances have no credentials to check.
+		creds, err := config.Credentials(relationship)
+		if err != nil || creds.Service != service {
+			continue
+		}
 
-  formatted := fmt.Sprintf("http://%s:%d/%s", creds.Host, creds.Port, creds.Path)
+		formatted, err := FormattedCredentials(creds)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", relationship, err)
+		}
+		endpoints[creds.Rel] = formatted
+	}
 
-  return formatted, nil
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("No relationship to the %s service", service)
+	}
 
+	return endpoints, nil
 }
@@ -19,3 +19,69 @@ func TestGoSolrFormatterCalled(t *testing.T) {
 
   helper.Equals(t, "http://solr.internal:8080/solr/collection1", formatted)
 }
+
+func TestGoSolrFormatterWithMultipleCores(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().WithServiceFixture(t, "solr", "8.6").Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("solrmain")
+	helper.Ok(t, err)
+
+	formatted, err := gosolr.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "http://solr.internal:8080/solr/maincore", formatted)
+
+	credentials, err = config.Credentials("solrextra")
+	helper.Ok(t, err)
+
+	formatted, err = gosolr.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "http://solr.internal:8080/solr/extracore", formatted)
+}
+
+func TestGoSolrBaseURLAndCore(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("solr")
+	helper.Ok(t, err)
+
+	base, err := gosolr.BaseURL(credentials)
+	helper.Ok(t, err)
+	helper.Equals(t, "http://solr.internal:8080/solr", base)
+	helper.Equals(t, "collection1", gosolr.Core(credentials))
+}
+
+func TestGoSolrFormatterHonoursScheme(t *testing.T) {
+	formatted, err := gosolr.FormattedCredentials(psh.Credential{Scheme: "https", Host: "solr.example.com", Port: 8443, Path: "solr/main"})
+	helper.Ok(t, err)
+	helper.Equals(t, "https://solr.example.com:8443/solr/main", formatted)
+
+	formatted, err = gosolr.FormattedCredentials(psh.Credential{Scheme: "solr", Host: "solr.internal", Port: 8080})
+	helper.Ok(t, err)
+	helper.Equals(t, "http://solr.internal:8080", formatted)
+
+	_, err = gosolr.FormattedCredentials(psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306})
+	helper.Assert(t, err != nil, "FormattedCredentials() accepted a MySQL relationship.")
+}
+
+func TestGoSolrEndpoints(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().
+		WithServiceFixture(t, "solr", "8.6").
+		WithServiceFixture(t, "redis", "6.0").
+		WithRelationship("empty").
+		Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	endpoints, err := gosolr.Endpoints(config, "solr")
+	helper.Ok(t, err)
+	helper.Equals(t, map[string]string{
+		"main":  "http://solr.internal:8080/solr/maincore",
+		"extra": "http://solr.internal:8080/solr/extracore",
+	}, endpoints)
+
+	_, err = gosolr.Endpoints(config, "missing")
+	helper.Assert(t, err != nil, "Endpoints() returned no error for a missing service.")
+}
@@ -0,0 +1,118 @@
+// The httpservice package connects to relationships that are plain HTTP
+// endpoints, such as Varnish or another application of the project, and
+// sends purge and ban requests to Varnish.
+package httpservice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The timeouts used by NewHttpClient().  Requests between containers of a
+// project should be fast; anything slower is better reported than waited for.
+const (
+	DefaultTimeout       = 30 * time.Second
+	DefaultDialTimeout   = 5 * time.Second
+	DefaultHeaderTimeout = 10 * time.Second
+)
+
+// Returns the base URL of an HTTP relationship, such as
+// "http://varnish.internal:8080".  The relationship path, if any, is included.
+func FormattedCredentials(creds psh.Credential) (string, error) {
+	scheme := creds.Scheme
+	switch scheme {
+	case "":
+		scheme = "http"
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("Not an HTTP relationship: %s", creds.Scheme)
+	}
+	if creds.Host == "" {
+		return "", fmt.Errorf("The HTTP relationship has no host")
+	}
+
+	u := url.URL{Scheme: scheme, Host: creds.Host}
+	if creds.Port != 0 {
+		u.Host = net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port))
+	}
+	if path := strings.Trim(creds.Path, "/"); path != "" {
+		u.Path = "/" + path
+	}
+
+	return u.String(), nil
+}
+
+// Returns an *http.Client with a timeout for whole requests, and shorter ones
+// for connecting (DefaultDialTimeout) and waiting for response headers
+// (DefaultHeaderTimeout, or the whole timeout if that is shorter).  A timeout
+// of zero means DefaultTimeout.
+func NewHttpClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	headerTimeout := DefaultHeaderTimeout
+	if timeout < headerTimeout {
+		headerTimeout = timeout
+	}
+
+	dialer := &net.Dialer{Timeout: DefaultDialTimeout, KeepAlive: 30 * time.Second}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   DefaultDialTimeout,
+			ResponseHeaderTimeout: headerTimeout,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConnsPerHost:   10,
+		},
+	}
+}
+
+// An HTTP relationship, with a client to call it.
+type Service struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// Returns a Service for an HTTP relationship, with a client from
+// NewHttpClient().
+func New(creds psh.Credential) (*Service, error) {
+	base, err := FormattedCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{BaseURL: base, Client: NewHttpClient(0)}, nil
+}
+
+// Returns the URL of a path on the service.
+func (s *Service) URL(path string) string {
+	return s.BaseURL + "/" + strings.TrimPrefix(path, "/")
+}
+
+// Sends a request to a path on the service.
+func (s *Service) Do(ctx context.Context, method string, path string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequest(method, s.URL(path), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if host := header.Get("Host"); host != "" {
+		req.Host = host
+	}
+
+	return s.Client.Do(req)
+}
@@ -0,0 +1,105 @@
+package httpservice_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	httpservice "github.com/platformsh/config-reader-go/v2/httpservice"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestHttpServiceFormattedCredentials(t *testing.T) {
+	formatted, err := httpservice.FormattedCredentials(helper.ServiceCredential(t, "varnish", "6.0", "varnish"))
+	helper.Ok(t, err)
+	helper.Equals(t, "http://varnish.internal:8080", formatted)
+
+	// An app-to-app relationship.
+	formatted, err = httpservice.FormattedCredentials(psh.Credential{Scheme: "http", Host: "api.internal", Port: 80, Path: "/v1/", Service: "api", Type: "golang:1.14"})
+	helper.Ok(t, err)
+	helper.Equals(t, "http://api.internal:80/v1", formatted)
+
+	_, err = httpservice.FormattedCredentials(psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306})
+	helper.Assert(t, err != nil, "FormattedCredentials() accepted a MySQL relationship.")
+}
+
+func TestHttpServiceClientHasTimeouts(t *testing.T) {
+	client := httpservice.NewHttpClient(0)
+	helper.Equals(t, httpservice.DefaultTimeout, client.Timeout)
+	helper.Equals(t, httpservice.DefaultHeaderTimeout, client.Transport.(*http.Transport).ResponseHeaderTimeout)
+
+	client = httpservice.NewHttpClient(2 * time.Second)
+	helper.Equals(t, 2*time.Second, client.Timeout)
+	// The header timeout can't be longer than the whole request.
+	helper.Equals(t, 2*time.Second, client.Transport.(*http.Transport).ResponseHeaderTimeout)
+}
+
+func TestHttpServiceCallsService(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Method + " " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	service, err := httpservice.New(helper.PointAt(t, psh.Credential{Scheme: "http", Path: "v1"}, server))
+	helper.Ok(t, err)
+	helper.Equals(t, server.URL+"/v1/users", service.URL("/users"))
+
+	resp, err := service.Do(context.Background(), http.MethodGet, "users", nil)
+	helper.Ok(t, err)
+	defer resp.Body.Close()
+	helper.Equals(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestVarnishPurgeAndBan(t *testing.T) {
+	var mu sync.Mutex
+	requests := make([]*http.Request, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r)
+		mu.Unlock()
+		if r.Method != "PURGE" && r.Method != "BAN" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	varnish, err := httpservice.NewVarnish(helper.PointAt(t, helper.ServiceCredential(t, "varnish", "6.0", "varnish"), server))
+	helper.Ok(t, err)
+
+	helper.Ok(t, varnish.Purge(context.Background(), "https://www.example.com/blog/?page=2"))
+	helper.Ok(t, varnish.Ban(context.Background(), "www.example.com", "^/blog/"))
+	helper.Ok(t, varnish.Ban(context.Background(), "", "\\.css$"))
+
+	mu.Lock()
+	defer mu.Unlock()
+	helper.Equals(t, 3, len(requests))
+
+	helper.Equals(t, "PURGE", requests[0].Method)
+	helper.Equals(t, "www.example.com", requests[0].Host)
+	helper.Equals(t, "/blog/?page=2", requests[0].URL.RequestURI())
+
+	helper.Equals(t, "BAN", requests[1].Method)
+	helper.Equals(t, "^/blog/", requests[1].Header.Get(httpservice.BanUrlHeader))
+	helper.Equals(t, "www.example.com", requests[1].Header.Get(httpservice.BanHostHeader))
+
+	helper.Equals(t, ".", requests[2].Header.Get(httpservice.BanHostHeader))
+}
+
+func TestVarnishReportsRefusals(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	varnish, err := httpservice.NewVarnish(helper.PointAt(t, helper.ServiceCredential(t, "varnish", "6.0", "varnish"), server))
+	helper.Ok(t, err)
+
+	helper.Assert(t, varnish.Purge(context.Background(), "https://www.example.com/") != nil, "Purge() ignored an error status.")
+
+	_, err = httpservice.NewVarnish(psh.Credential{Scheme: "http", Host: "api.internal", Type: "golang:1.14"})
+	helper.Assert(t, err != nil, "NewVarnish() accepted an application relationship.")
+}
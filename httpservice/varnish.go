@@ -0,0 +1,91 @@
+package httpservice
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The headers Ban() sends the URL and host patterns in.  Both are always set,
+// the host pattern to "." for all hosts, so the VCL of the Varnish service can
+// turn them into a ban directly, for example:
+//
+//	if (req.method == "BAN") {
+//	    ban("req.http.host ~ " + req.http.X-Ban-Host + " && req.url ~ " + req.http.X-Ban-Url);
+//	    return (synth(200, "Banned"));
+//	}
+const (
+	BanUrlHeader  = "X-Ban-Url"
+	BanHostHeader = "X-Ban-Host"
+)
+
+// A relationship to a Varnish service, which accepts PURGE and BAN requests
+// if its VCL allows them.
+type Varnish struct {
+	*Service
+}
+
+// Returns a Varnish for a relationship to a Varnish service.
+func NewVarnish(creds psh.Credential) (*Varnish, error) {
+	if !strings.HasPrefix(creds.Type, "varnish:") {
+		return nil, fmt.Errorf("Not a Varnish relationship: %s", creds.Type)
+	}
+
+	service, err := New(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Varnish{Service: service}, nil
+}
+
+// Removes the cached object of a public URL, such as
+// "https://www.example.com/blog/?page=2", by sending a PURGE request for its
+// path with its host.
+func (v *Varnish) Purge(ctx context.Context, publicURL string) error {
+	u, err := url.Parse(publicURL)
+	if err != nil {
+		return err
+	}
+
+	header := http.Header{}
+	header.Set("Host", u.Host)
+
+	return v.send(ctx, "PURGE", u.RequestURI(), header)
+}
+
+// Invalidates every cached object whose URL matches a regular expression, on
+// a host matching another, or on all hosts if host is empty.  See
+// BanUrlHeader for the VCL this needs.
+func (v *Varnish) Ban(ctx context.Context, host string, urlPattern string) error {
+	if host == "" {
+		host = "."
+	}
+
+	header := http.Header{}
+	header.Set(BanUrlHeader, urlPattern)
+	header.Set(BanHostHeader, host)
+
+	return v.send(ctx, "BAN", "/", header)
+}
+
+func (v *Varnish) send(ctx context.Context, method string, path string, header http.Header) error {
+	resp, err := v.Do(ctx, method, path, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Varnish returned HTTP %d for %s %s", resp.StatusCode, method, path)
+	}
+
+	return nil
+}
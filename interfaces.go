@@ -0,0 +1,59 @@
+package platformconfig
+
+// The values available in both the build and runtime environments, as
+// provided by BuildConfig.
+type BuildInfo interface {
+	ApplicationName() string
+	TreeId() string
+	AppDir() string
+	Project() string
+	ProjectEntropy() string
+	Variable(name string, defaultValue string) string
+	Variables() EnvList
+}
+
+// Access to the credentials of relationships, as provided by RuntimeConfig.
+type CredentialProvider interface {
+	Credentials(relationship string) (Credential, error)
+	AllCredentials(relationship string) ([]Credential, error)
+	Relationships() []string
+}
+
+// Access to the routes definition, as provided by RuntimeConfig.
+type RouteProvider interface {
+	Routes() Routes
+	Route(id string) (Route, bool)
+	PrimaryRoute() (Route, bool)
+	UpstreamRoutes() Routes
+	UpstreamRoutesForApp(appName string) Routes
+}
+
+// The values available in the runtime environment, as provided by
+// RuntimeConfig.
+//
+// Code that depends on this interface rather than on RuntimeConfig can be
+// tested with the in-memory implementation in the fake package.
+type RuntimeInfo interface {
+	BuildInfo
+	CredentialProvider
+	RouteProvider
+
+	Branch() string
+	Environment() string
+	DocumentRoot() string
+	SmtpHost() string
+	EmailEnabled() bool
+	Port() string
+	Socket() string
+
+	OnDedicated() bool
+	OnProduction() bool
+	OnStaging() bool
+	OnDevelopment() bool
+	EnvironmentType() string
+}
+
+var (
+	_ BuildInfo   = (*BuildConfig)(nil)
+	_ RuntimeInfo = (*RuntimeConfig)(nil)
+)
@@ -19,3 +19,16 @@ func TestLibPQFormatterCalled(t *testing.T) {
 
 	helper.Equals(t, "host=postgresql.internal port=5432 user=main password=main dbname=main sslmode=disable", formatted)
 }
+
+func TestLibPQFormatterWithServiceFixture(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().WithServiceFixture(t, "postgresql", "12").Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("postgresql")
+	helper.Ok(t, err)
+
+	formatted, err := libpq.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "host=postgresql.internal port=5432 user=main password=main dbname=main sslmode=disable", formatted)
+}
@@ -1,13 +1,152 @@
 package mongo
 
 import (
-  "fmt"
-  psh "github.com/platformsh/config-reader-go/v2"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	psh "github.com/platformsh/config-reader-go/v2"
 )
 
 // The mongo-go-driver requires a specific string to connect to MongoDB.
 func FormattedCredentials(creds psh.Credential) (string, error) {
-  formatted := fmt.Sprintf("%s://%s:%s@%s:%d/%s",
-    creds.Scheme, creds.Username, creds.Password, creds.Host, creds.Port, creds.Path)
-  return formatted, nil
+	conn, err := NewConnection([]psh.Credential{creds}, Options{})
+	if err != nil {
+		return "", err
+	}
+
+	return conn.URI(), nil
+}
+
+// Connection string options.  Empty values are left out of the URI, so that
+// the driver defaults apply.
+type Options struct {
+	// The database to authenticate against.
+	AuthSource string
+
+	// The name of the replica set.
+	ReplicaSet string
+
+	// Whether to retry writes once on network errors.
+	RetryWrites *bool
+
+	// Whether to connect with TLS.
+	Tls bool
+
+	// One of "primary", "primaryPreferred", "secondary", "secondaryPreferred"
+	// or "nearest".
+	ReadPreference string
+}
+
+// The parts of a connection string, for drivers configured with a structured
+// value rather than a URI.
+type Connection struct {
+	Scheme   string
+	Username string
+	Password string
+
+	// The host:port addresses of the members of the replica set, in the order
+	// of the relationship instances.
+	Hosts []string
+
+	Database string
+	Options  Options
+}
+
+// Combines the instances of a relationship into a Connection, with every
+// instance in the seed list.
+func NewConnection(creds []psh.Credential, opts Options) (*Connection, error) {
+	if len(creds) == 0 {
+		return nil, errors.New("No MongoDB instances given")
+	}
+
+	first := creds[0]
+	conn := &Connection{
+		Scheme:   first.Scheme,
+		Username: first.Username,
+		Password: first.Password,
+		Database: first.Path,
+		Options:  opts,
+	}
+	if conn.Scheme == "" {
+		conn.Scheme = "mongodb"
+	}
+
+	seen := make(map[string]bool)
+	for _, instance := range creds {
+		if instance.Username != first.Username || instance.Path != first.Path {
+			return nil, fmt.Errorf("MongoDB instances %s and %s have different credentials", first.Host, instance.Host)
+		}
+
+		host := net.JoinHostPort(instance.Host, strconv.Itoa(instance.Port))
+		if !seen[host] {
+			seen[host] = true
+			conn.Hosts = append(conn.Hosts, host)
+		}
+	}
+
+	return conn, nil
+}
+
+// Reads every instance of a relationship and returns both the connection
+// string and its parts.
+func FormattedRelationship(config psh.CredentialProvider, relationship string, opts Options) (string, *Connection, error) {
+	creds, err := config.AllCredentials(relationship)
+	if err != nil {
+		return "", nil, err
+	}
+
+	conn, err := NewConnection(creds, opts)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return conn.URI(), conn, nil
+}
+
+// Returns the connection string, with the username and password escaped.
+func (c *Connection) URI() string {
+	var b strings.Builder
+
+	b.WriteString(c.Scheme)
+	b.WriteString("://")
+	if c.Username != "" || c.Password != "" {
+		b.WriteString(url.UserPassword(c.Username, c.Password).String())
+		b.WriteString("@")
+	}
+	b.WriteString(strings.Join(c.Hosts, ","))
+	b.WriteString("/")
+	b.WriteString(url.PathEscape(c.Database))
+
+	if query := c.Options.values().Encode(); query != "" {
+		b.WriteString("?")
+		b.WriteString(query)
+	}
+
+	return b.String()
+}
+
+func (o Options) values() url.Values {
+	values := make(url.Values)
+
+	if o.AuthSource != "" {
+		values.Set("authSource", o.AuthSource)
+	}
+	if o.ReplicaSet != "" {
+		values.Set("replicaSet", o.ReplicaSet)
+	}
+	if o.RetryWrites != nil {
+		values.Set("retryWrites", strconv.FormatBool(*o.RetryWrites))
+	}
+	if o.Tls {
+		values.Set("tls", "true")
+	}
+	if o.ReadPreference != "" {
+		values.Set("readPreference", o.ReadPreference)
+	}
+
+	return values
 }
@@ -0,0 +1,145 @@
+package platformconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The possible values of Mount.Source.
+const (
+	MountSourceLocal   = "local"
+	MountSourceService = "service"
+	MountSourceTmp     = "tmp"
+)
+
+// A writable directory defined in the `mounts` section of the application.
+type Mount struct {
+	// The absolute path of the mount, resolved against AppDir().  This field
+	// is not part of the JSON definition, but it gets added to the struct from
+	// the JSON object key.
+	Path string
+
+	// The mount path exactly as written in the application definition.
+	Name string
+
+	Source     string `json:"source"`
+	SourcePath string `json:"source_path"`
+
+	// The name of the network-storage service backing the mount, if Source is
+	// "service".
+	Service string `json:"service"`
+}
+
+// Returns the mounts defined for the application, sorted by path.
+func (p *BuildConfig) Mounts() []Mount {
+	return p.mounts
+}
+
+// Returns the mounts with the given source type ("local", "service" or "tmp"),
+// sorted by path.
+func (p *BuildConfig) MountsBySource(source string) []Mount {
+	ret := make([]Mount, 0)
+
+	for _, mount := range p.mounts {
+		if mount.Source == source {
+			ret = append(ret, mount)
+		}
+	}
+
+	return ret
+}
+
+// Returns the mount containing a path.  Relative paths are resolved against
+// AppDir().  Its second return is false if the path is not inside any mount.
+func (p *BuildConfig) MountForPath(filePath string) (Mount, bool) {
+	filePath = p.resolvePath(filePath)
+
+	// Mounts are sorted by path, so the last match is the most specific one.
+	var found *Mount
+	for i, mount := range p.mounts {
+		if pathContains(mount.Path, filePath) {
+			found = &p.mounts[i]
+		}
+	}
+
+	if found == nil {
+		return Mount{}, false
+	}
+	return *found, true
+}
+
+// Verifies that a path can be written to at runtime, so that misconfigured
+// mounts can be detected at startup rather than on the first upload.
+//
+// The path must be inside a mount, and its directory must exist and accept
+// new files.  Relative paths are resolved against AppDir().
+func (p *RuntimeConfig) CheckWritable(filePath string) error {
+	filePath = p.resolvePath(filePath)
+
+	if _, ok := p.MountForPath(filePath); !ok {
+		return fmt.Errorf("Path is not inside a writable mount: %s", filePath)
+	}
+
+	dir := filePath
+	if info, err := os.Stat(filePath); err != nil || !info.IsDir() {
+		dir = path.Dir(filePath)
+	}
+
+	probe, err := ioutil.TempFile(dir, ".writable-")
+	if err != nil {
+		return fmt.Errorf("Path is not writable: %s", err)
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
+
+func (p *BuildConfig) resolvePath(filePath string) string {
+	if path.IsAbs(filePath) {
+		return path.Clean(filePath)
+	}
+	return path.Join(p.appDir, filePath)
+}
+
+// Determines if a path is the same as, or inside, a directory.
+func pathContains(dir string, filePath string) bool {
+	return filePath == dir || strings.HasPrefix(filePath, strings.TrimSuffix(dir, "/")+"/")
+}
+
+// Map the mounts section of the application definition into the appropriate
+// data structure.
+func extractMounts(application map[string]interface{}, appDir string) ([]Mount, error) {
+	var raw map[string]json.RawMessage
+	if err := decodeApplicationKey(application, "mounts", &raw); err != nil {
+		return nil, err
+	}
+
+	mounts := make([]Mount, 0, len(raw))
+	for name, definition := range raw {
+		var mount Mount
+
+		// Older applications define mounts as a "shared:files/<source path>" string.
+		var legacy string
+		if err := json.Unmarshal(definition, &legacy); err == nil {
+			mount.Source = MountSourceLocal
+			mount.SourcePath = strings.TrimPrefix(legacy, "shared:files/")
+		} else if err := json.Unmarshal(definition, &mount); err != nil {
+			return nil, fmt.Errorf("Invalid application mount %s: %s", name, err)
+		}
+
+		mount.Name = name
+		mount.Path = path.Join(appDir, "/"+name)
+		mounts = append(mounts, mount)
+	}
+
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].Path < mounts[j].Path
+	})
+
+	return mounts, nil
+}
@@ -0,0 +1,84 @@
+package platformconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestMountsAreResolvedAgainstAppDir(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	mounts := config.Mounts()
+
+	helper.Equals(t, 4, len(mounts))
+	helper.Equals(t, "/app/private", mounts[0].Path)
+	helper.Equals(t, "private", mounts[0].Name)
+	helper.Equals(t, psh.MountSourceLocal, mounts[0].Source)
+	helper.Equals(t, "private", mounts[0].SourcePath)
+	helper.Equals(t, "/app/shared", mounts[1].Path)
+	helper.Equals(t, "files", mounts[1].Service)
+	helper.Equals(t, "/app/web/uploads", mounts[2].Path)
+	helper.Equals(t, "/web/uploads", mounts[2].Name)
+}
+
+func TestMountsBySource(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	local := config.MountsBySource(psh.MountSourceLocal)
+	service := config.MountsBySource(psh.MountSourceService)
+	missing := config.MountsBySource("nope")
+
+	helper.Equals(t, 2, len(local))
+	helper.Equals(t, 1, len(service))
+	helper.Equals(t, "/app/shared", service[0].Path)
+	helper.Equals(t, 0, len(missing))
+}
+
+func TestMountForPathFindsMostSpecificMount(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	mount, ok := config.MountForPath("web/uploads/cache/thumb.png")
+	helper.Equals(t, true, ok)
+	helper.Equals(t, psh.MountSourceTmp, mount.Source)
+
+	mount, ok = config.MountForPath("/app/web/uploads/avatar.png")
+	helper.Equals(t, true, ok)
+	helper.Equals(t, "/app/web/uploads", mount.Path)
+
+	_, ok = config.MountForPath("/app/web/uploads-old/avatar.png")
+	helper.Equals(t, false, ok)
+
+	_, ok = config.MountForPath("web/index.php")
+	helper.Equals(t, false, ok)
+}
+
+func TestCheckWritable(t *testing.T) {
+	appDir, err := ioutil.TempDir("", "config-reader-go")
+	helper.Ok(t, err)
+	defer os.RemoveAll(appDir)
+
+	helper.Ok(t, os.MkdirAll(filepath.Join(appDir, "web", "uploads"), 0755))
+
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_APP_DIR": filepath.ToSlash(appDir),
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Ok(t, config.CheckWritable("web/uploads"))
+	helper.Ok(t, config.CheckWritable("web/uploads/avatar.png"))
+
+	err = config.CheckWritable("web/index.php")
+	helper.Assert(t, err != nil, "CheckWritable() accepted a path outside of any mount.")
+
+	// The mount is defined but its directory doesn't exist.
+	err = config.CheckWritable("private/key.pem")
+	helper.Assert(t, err != nil, "CheckWritable() accepted a missing mount directory.")
+}
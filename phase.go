@@ -0,0 +1,84 @@
+package platformconfig
+
+// The lifecycle phases of an application, as returned by Phase().
+const (
+	PhaseBuild      = "build"
+	PhaseDeploy     = "deploy"
+	PhasePostDeploy = "post_deploy"
+	PhaseWeb        = "web"
+	PhaseWorker     = "worker"
+)
+
+// The environment variable hook scripts set to tell Go tooling which hook is
+// running.
+//
+// The platform runs the deploy and post_deploy hooks with the same environment
+// as the web process, so they can't be told apart otherwise.  Values other than
+// the phase constants are ignored, so that a typo in a hook script can't stop
+// the application from starting.  For example:
+//
+//	hooks:
+//	    deploy: |
+//	        CONFIG_READER_PHASE=deploy ./bin/migrate
+const PhaseVariable = "CONFIG_READER_PHASE"
+
+// The hook scripts defined in the `hooks` section of the application.
+type Hooks struct {
+	Build      string `json:"build"`
+	Deploy     string `json:"deploy"`
+	PostDeploy string `json:"post_deploy"`
+}
+
+// Returns the script for a hook phase ("build", "deploy" or "post_deploy"),
+// or an empty string if there is none.
+func (h Hooks) Script(phase string) string {
+	switch phase {
+	case PhaseBuild:
+		return h.Build
+	case PhaseDeploy:
+		return h.Deploy
+	case PhasePostDeploy:
+		return h.PostDeploy
+	}
+	return ""
+}
+
+// Returns the hook scripts defined for the application.
+func (p *BuildConfig) Hooks() Hooks {
+	return p.hooks
+}
+
+// The lifecycle phase the current process is running in: "build", "deploy",
+// "post_deploy", "web" or "worker".
+//
+// The build phase is detected by the absence of runtime variables, and worker
+// instances from the application definition.  The deploy and post_deploy hooks
+// must be flagged by setting PhaseVariable, otherwise they're reported as "web".
+func (p *BuildConfig) Phase() string {
+	return p.phase
+}
+
+// Determines if the current process is running from one of the hooks.
+func (p *BuildConfig) InHook() bool {
+	return p.phase == PhaseBuild || p.phase == PhaseDeploy || p.phase == PhasePostDeploy
+}
+
+// Work out the current lifecycle phase.
+func detectPhase(getter envReader, varPrefix string, worker *Worker) string {
+	switch hint := getter(PhaseVariable); hint {
+	case PhaseBuild, PhaseDeploy, PhasePostDeploy, PhaseWeb, PhaseWorker:
+		return hint
+	}
+
+	// Not flagged by a hook script, or flagged with an unknown value, so fall
+	// back to detection.
+	if getter(varPrefix+"BRANCH") == "" {
+		return PhaseBuild
+	}
+
+	if worker != nil {
+		return PhaseWorker
+	}
+
+	return PhaseWeb
+}
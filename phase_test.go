@@ -0,0 +1,74 @@
+package platformconfig_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestPhaseInBuildIsBuild(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseBuild, config.Phase())
+	helper.Assert(t, config.InHook(), "InHook() returned false during the build.")
+}
+
+func TestPhaseAtRuntimeIsWeb(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseWeb, config.Phase())
+	helper.Assert(t, !config.InHook(), "InHook() returned true on the web process.")
+}
+
+func TestPhaseOnWorkerIsWorker(t *testing.T) {
+	application := `{"name": "app", "worker": {"name": "queue"}}`
+
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		"PLATFORM_APPLICATION": base64.StdEncoding.EncodeToString([]byte(application)),
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseWorker, config.Phase())
+}
+
+func TestPhaseFlaggedByHook(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		psh.PhaseVariable: "post_deploy",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhasePostDeploy, config.Phase())
+	helper.Assert(t, config.InHook(), "InHook() returned false in the post_deploy hook.")
+}
+
+func TestInvalidPhaseFlagFallsBackToDetection(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.RuntimeEnv(psh.EnvList{
+		psh.PhaseVariable: "predeploy",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseWeb, config.Phase())
+
+	build, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{
+		psh.PhaseVariable: "postdeploy",
+	}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	helper.Equals(t, psh.PhaseBuild, build.Phase())
+}
+
+func TestHooksAreTyped(t *testing.T) {
+	config, err := psh.NewBuildConfigReal(helper.BuildEnv(psh.EnvList{}), "PLATFORM_")
+	helper.Ok(t, err)
+
+	hooks := config.Hooks()
+
+	helper.Equals(t, "set -e\n", hooks.Build)
+	helper.Equals(t, "set -e\n", hooks.Script(psh.PhaseDeploy))
+	helper.Equals(t, "", hooks.PostDeploy)
+	helper.Equals(t, "", hooks.Script(psh.PhaseWeb))
+}
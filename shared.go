@@ -0,0 +1,67 @@
+package platformconfig
+
+import (
+	"sync"
+)
+
+// The configs shared by SharedBuildConfig() and SharedRuntimeConfig(), each
+// read from the environment the first time it's requested.
+type sharedConfigs struct {
+	buildOnce sync.Once
+	build     *BuildConfig
+	buildErr  error
+
+	runtimeOnce sync.Once
+	runtime     *RuntimeConfig
+	runtimeErr  error
+}
+
+var (
+	sharedMu sync.Mutex
+	shared   = &sharedConfigs{}
+)
+
+func currentShared() *sharedConfigs {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	return shared
+}
+
+// Returns a BuildConfig shared by the whole process.  It is read from the
+// environment on the first call, and every later call returns the same config
+// and error.  It is safe to call from multiple goroutines.
+//
+// The returned config must not be modified.
+func SharedBuildConfig() (*BuildConfig, error) {
+	s := currentShared()
+	s.buildOnce.Do(func() {
+		s.build, s.buildErr = NewBuildConfig()
+	})
+
+	return s.build, s.buildErr
+}
+
+// Returns a RuntimeConfig shared by the whole process.  It is read from the
+// environment on the first call, and every later call returns the same config
+// and error.  It is safe to call from multiple goroutines.
+//
+// The returned config must not be modified.
+func SharedRuntimeConfig() (*RuntimeConfig, error) {
+	s := currentShared()
+	s.runtimeOnce.Do(func() {
+		s.runtime, s.runtimeErr = NewRuntimeConfig()
+	})
+
+	return s.runtime, s.runtimeErr
+}
+
+// Discards the shared configs, so that the next call to SharedBuildConfig()
+// or SharedRuntimeConfig() reads the environment again.  This is meant for
+// tests that change the environment.
+func ResetShared() {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	shared = &sharedConfigs{}
+}
@@ -0,0 +1,87 @@
+package platformconfig_test
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+// Set environment variables for the duration of a test.
+func setenv(t *testing.T, env psh.EnvList) func() {
+	previous := make(map[string]*string)
+	for key, val := range env {
+		if old, ok := os.LookupEnv(key); ok {
+			previous[key] = &old
+		} else {
+			previous[key] = nil
+		}
+		helper.Ok(t, os.Setenv(key, val))
+	}
+
+	return func() {
+		for key, val := range previous {
+			if val == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *val)
+			}
+		}
+		psh.ResetShared()
+	}
+}
+
+func TestSharedRuntimeConfigIsShared(t *testing.T) {
+	defer setenv(t, psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "main",
+	})()
+	psh.ResetShared()
+
+	configs := make([]*psh.RuntimeConfig, 10)
+	errs := make([]error, 10)
+	var wg sync.WaitGroup
+	for i := range configs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			configs[i], errs[i] = psh.SharedRuntimeConfig()
+		}(i)
+	}
+	wg.Wait()
+
+	for i, config := range configs {
+		helper.Ok(t, errs[i])
+		helper.Assert(t, config == configs[0], "SharedRuntimeConfig() returned different configs.")
+	}
+	helper.Equals(t, "main", configs[0].Branch())
+
+	build, err := psh.SharedBuildConfig()
+	helper.Ok(t, err)
+	helper.Equals(t, "app", build.ApplicationName())
+}
+
+func TestSharedRuntimeConfigRemembersError(t *testing.T) {
+	defer setenv(t, psh.EnvList{
+		"PLATFORM_APPLICATION_NAME": "app",
+		"PLATFORM_BRANCH":           "",
+	})()
+	psh.ResetShared()
+
+	_, err := psh.SharedRuntimeConfig()
+	helper.Equals(t, psh.NotRuntimePlatform, err)
+
+	// The environment is only read once until the shared configs are reset.
+	os.Setenv("PLATFORM_BRANCH", "main")
+
+	_, err = psh.SharedRuntimeConfig()
+	helper.Equals(t, psh.NotRuntimePlatform, err)
+
+	psh.ResetShared()
+
+	config, err := psh.SharedRuntimeConfig()
+	helper.Ok(t, err)
+	helper.Equals(t, "main", config.Branch())
+}
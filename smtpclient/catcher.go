@@ -0,0 +1,211 @@
+package smtpclient
+
+import (
+	"io/ioutil"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// A message received by a Catcher.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// A Catcher is a minimal SMTP server that accepts every message and keeps it
+// in memory instead of delivering it.  Use it in place of the Platform.sh SMTP
+// server on development machines and in tests:
+//
+//	catcher, err := smtpclient.NewCatcher()
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer catcher.Close()
+//
+//	sender := catcher.Sender()
+//
+// A Catcher is safe for concurrent use.
+type Catcher struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	messages []Message
+	conns    map[net.Conn]bool
+	closed   bool
+	wg       sync.WaitGroup
+}
+
+// Starts a Catcher listening on a random port of the loopback interface.
+func NewCatcher() (*Catcher, error) {
+	return NewCatcherAt("127.0.0.1:0")
+}
+
+// Starts a Catcher listening on the given host:port address.
+func NewCatcherAt(addr string) (*Catcher, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catcher{listener: listener, conns: make(map[net.Conn]bool)}
+
+	c.wg.Add(1)
+	go c.serve()
+
+	return c, nil
+}
+
+// The host:port address the Catcher listens on.
+func (c *Catcher) Addr() string {
+	return c.listener.Addr().String()
+}
+
+// Returns a Sender that delivers to the Catcher.
+func (c *Catcher) Sender() *Sender {
+	return &Sender{Addr: c.Addr()}
+}
+
+// Returns the messages received so far, oldest first.
+func (c *Catcher) Messages() []Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Message(nil), c.messages...)
+}
+
+// Forgets the messages received so far.
+func (c *Catcher) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.messages = nil
+}
+
+// Stops accepting connections and closes the open ones.
+func (c *Catcher) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+
+	err := c.listener.Close()
+
+	c.mu.Lock()
+	for conn := range c.conns {
+		conn.Close()
+	}
+	c.mu.Unlock()
+
+	c.wg.Wait()
+
+	return err
+}
+
+func (c *Catcher) serve() {
+	defer c.wg.Done()
+
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		// A connection accepted while Close() runs would otherwise be missed
+		// by it, and keep Close() waiting.
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+			continue
+		}
+		c.conns[conn] = true
+		c.mu.Unlock()
+
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.handle(conn)
+
+			c.mu.Lock()
+			delete(c.conns, conn)
+			c.mu.Unlock()
+		}()
+	}
+}
+
+// Speak just enough SMTP for net/smtp and common mail libraries.
+func (c *Catcher) handle(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	var current Message
+
+	text.PrintfLine("220 localhost Mail catcher ready")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], line[i+1:]
+		}
+
+		switch strings.ToUpper(verb) {
+		case "HELO":
+			text.PrintfLine("250 localhost")
+		case "EHLO":
+			text.PrintfLine("250-localhost")
+			text.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			current = Message{From: parseAddress(arg)}
+			text.PrintfLine("250 OK")
+		case "RCPT":
+			current.To = append(current.To, parseAddress(arg))
+			text.PrintfLine("250 OK")
+		case "DATA":
+			if len(current.To) == 0 {
+				text.PrintfLine("503 No recipients")
+				continue
+			}
+			text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := ioutil.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			current.Data = data
+			c.mu.Lock()
+			c.messages = append(c.messages, current)
+			c.mu.Unlock()
+			current = Message{}
+			text.PrintfLine("250 OK")
+		case "RSET":
+			current = Message{}
+			text.PrintfLine("250 OK")
+		case "NOOP":
+			text.PrintfLine("250 OK")
+		case "QUIT":
+			text.PrintfLine("221 Bye")
+			return
+		default:
+			text.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// Extract the address from "FROM:<a@example.com> SIZE=123" and the like.
+func parseAddress(arg string) string {
+	if i := strings.IndexByte(arg, ':'); i >= 0 {
+		arg = arg[i+1:]
+	}
+	arg = strings.TrimSpace(arg)
+	if i := strings.IndexByte(arg, ' '); i >= 0 {
+		arg = arg[:i]
+	}
+
+	return strings.Trim(arg, "<>")
+}
@@ -0,0 +1,71 @@
+// The smtpclient package sends email through the Platform.sh SMTP server,
+// which listens on port 25 of SmtpHost() and requires no authentication, and
+// provides an in-process mail catcher to stand in for it in development and
+// tests.
+package smtpclient
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"strconv"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The port the Platform.sh SMTP server listens on.
+const Port = 25
+
+// Returned when outgoing email is disabled on the environment.
+var ErrEmailDisabled = errors.New("Outgoing email is disabled on this environment")
+
+// A Mailer sends a message with the same arguments as smtp.SendMail(), so that
+// code can be given a Sender or a stand-in.
+type Mailer interface {
+	SendMail(from string, to []string, msg []byte) error
+}
+
+// A Sender sends email through an SMTP server that requires no
+// authentication.
+type Sender struct {
+	// The host:port address of the SMTP server.
+	Addr string
+}
+
+var _ Mailer = (*Sender)(nil)
+
+// Returns the host:port address of the SMTP server of the environment.
+func Addr(config psh.RuntimeInfo) (string, error) {
+	if !config.EmailEnabled() {
+		return "", ErrEmailDisabled
+	}
+
+	return net.JoinHostPort(config.SmtpHost(), strconv.Itoa(Port)), nil
+}
+
+// Connects to the SMTP server of the environment.  The caller is responsible
+// for closing the client.
+func Dial(config psh.RuntimeInfo) (*smtp.Client, error) {
+	addr, err := Addr(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return smtp.Dial(addr)
+}
+
+// Returns a Sender for the SMTP server of the environment.
+func NewSender(config psh.RuntimeInfo) (*Sender, error) {
+	addr, err := Addr(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Sender{Addr: addr}, nil
+}
+
+// Sends a message, as smtp.SendMail() does.  msg should be an RFC 822-style
+// email with headers first, a blank line, and then the message body.
+func (s *Sender) SendMail(from string, to []string, msg []byte) error {
+	return smtp.SendMail(s.Addr, nil, from, to, msg)
+}
@@ -0,0 +1,103 @@
+package smtpclient_test
+
+import (
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/platformsh/config-reader-go/v2/fake"
+	"github.com/platformsh/config-reader-go/v2/smtpclient"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestAddrUsesPort25(t *testing.T) {
+	addr, err := smtpclient.Addr(fake.New().WithSmtpHost("1.2.3.4"))
+	helper.Ok(t, err)
+	helper.Equals(t, "1.2.3.4:25", addr)
+
+	sender, err := smtpclient.NewSender(fake.New().WithSmtpHost("1.2.3.4"))
+	helper.Ok(t, err)
+	helper.Equals(t, "1.2.3.4:25", sender.Addr)
+}
+
+func TestDisabledEmailIsReported(t *testing.T) {
+	config := fake.New()
+
+	_, err := smtpclient.Addr(config)
+	helper.Equals(t, smtpclient.ErrEmailDisabled, err)
+
+	_, err = smtpclient.Dial(config)
+	helper.Equals(t, smtpclient.ErrEmailDisabled, err)
+
+	_, err = smtpclient.NewSender(config)
+	helper.Equals(t, smtpclient.ErrEmailDisabled, err)
+}
+
+func TestCatcherReceivesMessages(t *testing.T) {
+	catcher, err := smtpclient.NewCatcher()
+	helper.Ok(t, err)
+	defer catcher.Close()
+
+	var mailer smtpclient.Mailer = catcher.Sender()
+	msg := "Subject: Hello\r\n\r\nHello there.\r\n.leading dot\r\n"
+	helper.Ok(t, mailer.SendMail("app@example.com", []string{"a@example.com", "b@example.com"}, []byte(msg)))
+
+	messages := catcher.Messages()
+	helper.Equals(t, 1, len(messages))
+	helper.Equals(t, "app@example.com", messages[0].From)
+	helper.Equals(t, []string{"a@example.com", "b@example.com"}, messages[0].To)
+	helper.Equals(t, strings.Replace(msg, "\r\n", "\n", -1), string(messages[0].Data))
+
+	catcher.Reset()
+	helper.Equals(t, 0, len(catcher.Messages()))
+}
+
+func TestCatcherClosesIdleConnections(t *testing.T) {
+	catcher, err := smtpclient.NewCatcher()
+	helper.Ok(t, err)
+
+	client, err := smtp.Dial(catcher.Addr())
+	helper.Ok(t, err)
+	defer client.Close()
+	helper.Ok(t, client.Hello("localhost"))
+
+	helper.Ok(t, catcher.Close())
+	helper.Assert(t, client.Noop() != nil, "The connection is still open after Close().")
+}
+
+func TestCatcherCloseDoesNotWaitForConnectingClients(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		catcher, err := smtpclient.NewCatcher()
+		helper.Ok(t, err)
+
+		// Clients that connect while Close() runs, and never hang up.
+		stop := make(chan bool)
+		for j := 0; j < 4; j++ {
+			go func() {
+				for {
+					select {
+					case <-stop:
+						return
+					default:
+					}
+					if conn, err := net.Dial("tcp", catcher.Addr()); err == nil {
+						defer conn.Close()
+					}
+				}
+			}()
+		}
+
+		closed := make(chan error)
+		go func() { closed <- catcher.Close() }()
+
+		select {
+		case err := <-closed:
+			helper.Ok(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Close() is waiting for a connection accepted while closing.")
+		}
+		close(stop)
+	}
+}
@@ -19,3 +19,24 @@ func TestSqlDsnFormatterCalled(t *testing.T) {
 
 	helper.Equals(t, "user:@tcp(database.internal:3306)/main?charset=utf8", formatted)
 }
+
+func TestSqlDsnFormatterWithMariaDBReplicas(t *testing.T) {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().WithServiceFixture(t, "mariadb", "10.4").Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	credentials, err := config.Credentials("database")
+	helper.Ok(t, err)
+
+	formatted, err := sqldsn.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "user:@tcp(database.internal:3306)/main?charset=utf8", formatted)
+
+	credentials, err = config.Credentials("database-replica")
+	helper.Ok(t, err)
+
+	formatted, err = sqldsn.FormattedCredentials(credentials)
+	helper.Ok(t, err)
+
+	helper.Equals(t, "user:@tcp(database-replica.internal:3306)/main?charset=utf8", formatted)
+}
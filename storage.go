@@ -0,0 +1,72 @@
+package platformconfig
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Determines whether a relationship points to a network-storage service.
+// Such relationships have no meaningful host or port for the application: the
+// storage is used through the mounts backed by the service.
+func IsNetworkStorage(creds Credential) bool {
+	return strings.HasPrefix(creds.Type, "network-storage:") || creds.Scheme == "nfs"
+}
+
+// Returns the mounts backed by a network-storage service, sorted by path.
+func (p *BuildConfig) MountsForService(service string) []Mount {
+	ret := make([]Mount, 0)
+
+	for _, mount := range p.mounts {
+		if mount.Source == MountSourceService && mount.Service == service {
+			ret = append(ret, mount)
+		}
+	}
+
+	return ret
+}
+
+// Returns the names of the relationships to network-storage services, sorted.
+func (p *RuntimeConfig) NetworkStorageRelationships() []string {
+	names := make([]string, 0)
+
+	for _, name := range p.Relationships() {
+		if creds, err := p.Credentials(name); err == nil && IsNetworkStorage(creds) {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// Returns the mounts backed by the network-storage service a relationship
+// points to, sorted by path.  It is an error for the relationship not to be a
+// network-storage one, or for no mount to use its service.
+func (p *RuntimeConfig) NetworkStorageMounts(relationship string) ([]Mount, error) {
+	creds, err := p.Credentials(relationship)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsNetworkStorage(creds) {
+		return nil, fmt.Errorf("Not a network-storage relationship: %s", relationship)
+	}
+
+	mounts := p.MountsForService(creds.Service)
+	if len(mounts) == 0 {
+		return nil, fmt.Errorf("No mount uses the network-storage service %s of relationship %s", creds.Service, relationship)
+	}
+
+	return mounts, nil
+}
+
+// Returns the local path of the storage shared between app containers through
+// a network-storage relationship: the absolute path of the first mount backed
+// by its service.
+func (p *RuntimeConfig) NetworkStoragePath(relationship string) (string, error) {
+	mounts, err := p.NetworkStorageMounts(relationship)
+	if err != nil {
+		return "", err
+	}
+
+	return mounts[0].Path, nil
+}
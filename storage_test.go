@@ -0,0 +1,70 @@
+package platformconfig_test
+
+import (
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func networkStorageConfig(t *testing.T) *psh.RuntimeConfig {
+	config, err := psh.NewRuntimeConfigReal(helper.NewEnvBuilder().
+		WithServiceFixture(t, "network-storage", "2.0").
+		WithServiceFixture(t, "redis", "6.0").
+		WithRelationship("orphan", psh.Credential{Scheme: "nfs", Service: "other", Type: "network-storage:2.0", Host: "other.internal"}).
+		WithRelationship("empty").
+		WithApplication(map[string]interface{}{
+			"name": "app",
+			"mounts": map[string]interface{}{
+				"web/uploads": map[string]interface{}{"source": "service", "service": "files", "source_path": "uploads"},
+				"exports":     map[string]interface{}{"source": "service", "service": "files", "source_path": "exports"},
+				"cache":       map[string]interface{}{"source": "local", "source_path": "cache"},
+			},
+		}).
+		Runtime(), "PLATFORM_")
+	helper.Ok(t, err)
+
+	return config
+}
+
+func TestNetworkStorageRelationshipsAreIdentified(t *testing.T) {
+	config := networkStorageConfig(t)
+
+	helper.Equals(t, []string{"files", "orphan"}, config.NetworkStorageRelationships())
+
+	creds, err := config.Credentials("redis")
+	helper.Ok(t, err)
+	helper.Assert(t, !psh.IsNetworkStorage(creds), "A Redis relationship was identified as network storage.")
+}
+
+func TestNetworkStoragePathFollowsMounts(t *testing.T) {
+	config := networkStorageConfig(t)
+
+	mounts, err := config.NetworkStorageMounts("files")
+	helper.Ok(t, err)
+	helper.Equals(t, 2, len(mounts))
+	helper.Equals(t, "/app/exports", mounts[0].Path)
+	helper.Equals(t, "/app/web/uploads", mounts[1].Path)
+
+	storagePath, err := config.NetworkStoragePath("files")
+	helper.Ok(t, err)
+	helper.Equals(t, "/app/exports", storagePath)
+
+	helper.Equals(t, 0, len(config.MountsForService("missing")))
+}
+
+func TestNetworkStoragePathErrors(t *testing.T) {
+	config := networkStorageConfig(t)
+
+	_, err := config.NetworkStoragePath("redis")
+	helper.Assert(t, err != nil, "NetworkStoragePath() accepted a Redis relationship.")
+
+	_, err = config.NetworkStoragePath("orphan")
+	helper.Assert(t, err != nil, "NetworkStoragePath() accepted a service with no mount.")
+
+	_, err = config.NetworkStoragePath("missing")
+	helper.Assert(t, err != nil, "NetworkStoragePath() accepted a missing relationship.")
+
+	_, err = config.NetworkStoragePath("empty")
+	helper.Assert(t, err != nil, "NetworkStoragePath() accepted a relationship without instances.")
+}
@@ -0,0 +1,156 @@
+package testdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// EnvBuilder assembles a simulated Platform.sh environment in code, so that a
+// test can declare exactly the relationships, routes and variables it needs
+// without editing shared fixture files.
+//
+// The With* methods modify the builder and return it, so that calls can be
+// chained:
+//
+//	getter := testdata.NewEnvBuilder().
+//		WithBranch("master").
+//		WithRelationship("database", psh.Credential{Scheme: "mysql", Host: "database.internal", Port: 3306}).
+//		WithVariable("somevar", "someval").
+//		Runtime()
+type EnvBuilder struct {
+	env           psh.EnvList
+	runtimeEnv    psh.EnvList
+	relationships psh.Credentials
+	routes        map[string]psh.Route
+	variables     psh.EnvList
+	application   map[string]interface{}
+}
+
+// Returns a builder for an application named "app" in a project named
+// "test-project", on the "feature-x" branch, with no relationships, routes or
+// variables.
+func NewEnvBuilder() *EnvBuilder {
+	return &EnvBuilder{
+		env: psh.EnvList{
+			"PLATFORM_APP_DIR":          "/app",
+			"PLATFORM_APPLICATION_NAME": "app",
+			"PLATFORM_PROJECT":          "test-project",
+			"PLATFORM_TREE_ID":          "abc123",
+			"PLATFORM_PROJECT_ENTROPY":  "def789",
+		},
+		runtimeEnv: psh.EnvList{
+			"PLATFORM_BRANCH":        "feature-x",
+			"PLATFORM_ENVIRONMENT":   "feature-x-hgi456",
+			"PLATFORM_DOCUMENT_ROOT": "/app/web",
+			"PLATFORM_SMTP_HOST":     "1.2.3.4",
+			"PORT":                   "8080",
+			"SOCKET":                 "unix://tmp/blah.sock",
+		},
+		relationships: make(psh.Credentials),
+		routes:        make(map[string]psh.Route),
+		variables:     make(psh.EnvList),
+		application:   map[string]interface{}{"name": "app"},
+	}
+}
+
+// Adds instances to a relationship.
+func (b *EnvBuilder) WithRelationship(name string, creds ...psh.Credential) *EnvBuilder {
+	b.relationships[name] = append(b.relationships[name], creds...)
+	return b
+}
+
+// Adds a route, keyed by its URL.
+func (b *EnvBuilder) WithRoute(url string, route psh.Route) *EnvBuilder {
+	b.routes[url] = route
+	return b
+}
+
+// Adds a variable to PLATFORM_VARIABLES.
+func (b *EnvBuilder) WithVariable(name string, value string) *EnvBuilder {
+	b.variables[name] = value
+	return b
+}
+
+// Replaces the application definition in PLATFORM_APPLICATION.  If the
+// definition has a name, PLATFORM_APPLICATION_NAME is set to it.
+func (b *EnvBuilder) WithApplication(application map[string]interface{}) *EnvBuilder {
+	b.application = application
+	if name, ok := application["name"].(string); ok && name != "" {
+		b.env["PLATFORM_APPLICATION_NAME"] = name
+	}
+	return b
+}
+
+// Sets the Git branch, and the environment ID to match it.
+func (b *EnvBuilder) WithBranch(branch string) *EnvBuilder {
+	b.runtimeEnv["PLATFORM_BRANCH"] = branch
+	b.runtimeEnv["PLATFORM_ENVIRONMENT"] = branch + "-hgi456"
+	return b
+}
+
+// Sets an arbitrary environment variable, such as PORT or
+// PLATFORM_ENVIRONMENT_TYPE.  Runtime-only variables set this way are also
+// visible to Build().
+func (b *EnvBuilder) WithEnv(key string, value string) *EnvBuilder {
+	b.env[key] = value
+	return b
+}
+
+// Simulates a Dedicated environment.
+func (b *EnvBuilder) OnDedicated() *EnvBuilder {
+	b.runtimeEnv["PLATFORM_MODE"] = "enterprise"
+	return b
+}
+
+// Returns the environment variables of the simulated runtime environment,
+// with the complex values base64-encoded the way the platform does.
+func (b *EnvBuilder) EnvList() psh.EnvList {
+	env := b.buildEnvList()
+
+	env["PLATFORM_RELATIONSHIPS"] = encodeJson(b.relationships)
+	env["PLATFORM_ROUTES"] = encodeJson(b.routes)
+
+	env = MergeMaps(env, b.runtimeEnv)
+
+	return MergeMaps(env, b.env)
+}
+
+// Returns a getter of the same signature as os.Getenv() that simulates the
+// runtime environment.
+func (b *EnvBuilder) Runtime() func(string) string {
+	return getter(b.EnvList())
+}
+
+// Returns a getter of the same signature as os.Getenv() that simulates the
+// build environment, which has no relationships, routes or runtime values.
+func (b *EnvBuilder) Build() func(string) string {
+	return getter(b.buildEnvList())
+}
+
+func (b *EnvBuilder) buildEnvList() psh.EnvList {
+	env := MergeMaps(psh.EnvList{}, b.env)
+
+	env["PLATFORM_VARIABLES"] = encodeJson(b.variables)
+	env["PLATFORM_APPLICATION"] = encodeJson(b.application)
+
+	return env
+}
+
+func encodeJson(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		// Only values that can't be represented as JSON end up here, which
+		// is a mistake in the test itself.
+		panic(err)
+	}
+
+	return base64.StdEncoding.EncodeToString(encoded)
+}
+
+func getter(env psh.EnvList) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
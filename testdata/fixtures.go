@@ -0,0 +1,157 @@
+package testdata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The JSON files making up a fixture directory, and the variable each
+// complex file is encoded into.  ENV.json holds the simple build-time
+// variables and ENV_runtime.json the simple runtime-only ones.
+var (
+	buildFiles = map[string]string{
+		"PLATFORM_VARIABLES.json":   "PLATFORM_VARIABLES",
+		"PLATFORM_APPLICATION.json": "PLATFORM_APPLICATION",
+	}
+	runtimeFiles = map[string]string{
+		"PLATFORM_RELATIONSHIPS.json": "PLATFORM_RELATIONSHIPS",
+		"PLATFORM_ROUTES.json":        "PLATFORM_ROUTES",
+	}
+)
+
+// A function reading a named file from a fixture directory.
+type fileReader func(name string) ([]byte, error)
+
+// Like LoadJsonFile(), but fails the test if the file can't be read or
+// isn't a JSON object of strings.
+func LoadJsonFileT(tb testing.TB, file string) psh.EnvList {
+	tb.Helper()
+
+	byteValue, err := ioutil.ReadFile(file)
+	if err != nil {
+		tb.Fatalf("Can't read fixture: %s", err)
+		return nil
+	}
+
+	return decodeEnvList(tb, file, byteValue)
+}
+
+// Like EncodeJsonFile(), but fails the test if the file can't be read or
+// isn't valid JSON.
+func EncodeJsonFileT(tb testing.TB, file string) string {
+	tb.Helper()
+
+	byteValue, err := ioutil.ReadFile(file)
+	if err != nil {
+		tb.Fatalf("Can't read fixture: %s", err)
+		return ""
+	}
+
+	return encodeJsonBytes(tb, file, byteValue)
+}
+
+// Produces a getter like BuildEnv(), reading the fixtures from a directory
+// with the same layout as this package: ENV.json, plus optionally
+// PLATFORM_VARIABLES.json and PLATFORM_APPLICATION.json.  Fails the test if
+// any of the files is missing or broken.
+func BuildEnvFromDir(tb testing.TB, dir string, env psh.EnvList) func(string) string {
+	tb.Helper()
+
+	return envFromFiles(tb, dirReader(dir), false, env)
+}
+
+// Produces a getter like RuntimeEnv(), reading the fixtures from a directory
+// with the same layout as this package: ENV.json and ENV_runtime.json, plus
+// optionally PLATFORM_VARIABLES.json, PLATFORM_APPLICATION.json,
+// PLATFORM_RELATIONSHIPS.json and PLATFORM_ROUTES.json.  Fails the test if any
+// of the files is missing or broken.
+func RuntimeEnvFromDir(tb testing.TB, dir string, env psh.EnvList) func(string) string {
+	tb.Helper()
+
+	return envFromFiles(tb, dirReader(dir), true, env)
+}
+
+func dirReader(dir string) fileReader {
+	return func(name string) ([]byte, error) {
+		return ioutil.ReadFile(filepath.Join(dir, name))
+	}
+}
+
+func envFromFiles(tb testing.TB, read fileReader, runtime bool, env psh.EnvList) func(string) string {
+	tb.Helper()
+
+	vars := loadEnvFile(tb, read, "ENV.json")
+	if vars == nil {
+		return nil
+	}
+
+	files := buildFiles
+	if runtime {
+		files = MergeMaps(MergeMaps(psh.EnvList{}, buildFiles), runtimeFiles)
+	}
+	for file, key := range files {
+		byteValue, err := read(file)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			tb.Fatalf("Can't read fixture %s: %s", file, err)
+			return nil
+		}
+		vars[key] = encodeJsonBytes(tb, file, byteValue)
+	}
+
+	if runtime {
+		runtimeVars := loadEnvFile(tb, read, "ENV_runtime.json")
+		if runtimeVars == nil {
+			return nil
+		}
+		vars = MergeMaps(vars, runtimeVars)
+	}
+
+	vars = MergeMaps(vars, env)
+
+	return getter(vars)
+}
+
+func loadEnvFile(tb testing.TB, read fileReader, file string) psh.EnvList {
+	tb.Helper()
+
+	byteValue, err := read(file)
+	if err != nil {
+		tb.Fatalf("Can't read fixture %s: %s", file, err)
+		return nil
+	}
+
+	return decodeEnvList(tb, file, byteValue)
+}
+
+func decodeEnvList(tb testing.TB, file string, byteValue []byte) psh.EnvList {
+	tb.Helper()
+
+	var result psh.EnvList
+	if err := json.Unmarshal(byteValue, &result); err != nil {
+		tb.Fatalf("Invalid fixture %s: %s", file, err)
+		return nil
+	}
+
+	return result
+}
+
+func encodeJsonBytes(tb testing.TB, file string, byteValue []byte) string {
+	tb.Helper()
+
+	var decoded interface{}
+	if err := json.Unmarshal(byteValue, &decoded); err != nil {
+		tb.Fatalf("Invalid fixture %s: %s", file, err)
+		return ""
+	}
+
+	return base64.StdEncoding.EncodeToString(byteValue)
+}
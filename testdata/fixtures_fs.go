@@ -0,0 +1,33 @@
+//go:build go1.16
+// +build go1.16
+
+package testdata
+
+import (
+	"io/fs"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// Produces a getter like BuildEnvFromDir(), reading the fixtures from the
+// root of a file system such as an embed.FS or os.DirFS().
+func BuildEnvFS(tb testing.TB, fsys fs.FS, env psh.EnvList) func(string) string {
+	tb.Helper()
+
+	return envFromFiles(tb, fsReader(fsys), false, env)
+}
+
+// Produces a getter like RuntimeEnvFromDir(), reading the fixtures from the
+// root of a file system such as an embed.FS or os.DirFS().
+func RuntimeEnvFS(tb testing.TB, fsys fs.FS, env psh.EnvList) func(string) string {
+	tb.Helper()
+
+	return envFromFiles(tb, fsReader(fsys), true, env)
+}
+
+func fsReader(fsys fs.FS) fileReader {
+	return func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	}
+}
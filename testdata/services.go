@@ -0,0 +1,112 @@
+package testdata
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The directory holding the service fixtures: one directory per service type,
+// with one file per service version, each holding the relationships an
+// application connected to that service sees in PLATFORM_RELATIONSHIPS.
+func servicesDir() string {
+	_, b, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(b), "services")
+}
+
+// Lists the available service fixtures, as a map of service type to its
+// versions.
+func ServiceFixtures(tb testing.TB) map[string][]string {
+	tb.Helper()
+
+	files, err := filepath.Glob(filepath.Join(servicesDir(), "*", "*.json"))
+	if err != nil {
+		tb.Fatalf("Can't list service fixtures: %s", err)
+		return nil
+	}
+
+	ret := make(map[string][]string)
+	for _, file := range files {
+		service := filepath.Base(filepath.Dir(file))
+		version := strings.TrimSuffix(filepath.Base(file), ".json")
+		ret[service] = append(ret[service], version)
+	}
+	for _, versions := range ret {
+		sort.Strings(versions)
+	}
+
+	return ret
+}
+
+// Loads the relationships of a service fixture, such as ("redis", "6.0").
+// Fails the test if there is no such fixture.
+func ServiceFixture(tb testing.TB, service string, version string) psh.Credentials {
+	tb.Helper()
+
+	file := filepath.Join(servicesDir(), service, version+".json")
+	byteValue, err := ioutil.ReadFile(file)
+	if err != nil {
+		tb.Fatalf("Can't read service fixture: %s", err)
+		return nil
+	}
+
+	var creds psh.Credentials
+	if err := json.Unmarshal(byteValue, &creds); err != nil {
+		tb.Fatalf("Invalid service fixture %s: %s", file, err)
+		return nil
+	}
+
+	return creds
+}
+
+// Returns the first instance of a relationship of a service fixture, such as
+// ("redis", "6.0", "redis").  Fails the test if there is no such fixture or
+// relationship.
+func ServiceCredential(tb testing.TB, service string, version string, relationship string) psh.Credential {
+	tb.Helper()
+
+	creds := ServiceFixture(tb, service, version)[relationship]
+	if len(creds) == 0 {
+		tb.Fatalf("No relationship %s in service fixture %s %s", relationship, service, version)
+		return psh.Credential{}
+	}
+
+	return creds[0]
+}
+
+// Points a credential at a local stand-in server: its host, IP and port are
+// set to those of the server.
+func PointAt(tb testing.TB, creds psh.Credential, server *httptest.Server) psh.Credential {
+	tb.Helper()
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	Ok(tb, err)
+
+	creds.Host = host
+	creds.Ip = host
+	creds.Port, err = strconv.Atoi(port)
+	Ok(tb, err)
+
+	return creds
+}
+
+// Adds the relationships of a service fixture, such as ("redis", "6.0").
+// Fails the test if there is no such fixture.
+func (b *EnvBuilder) WithServiceFixture(tb testing.TB, service string, version string) *EnvBuilder {
+	tb.Helper()
+
+	for name, creds := range ServiceFixture(tb, service, version) {
+		b.WithRelationship(name, creds...)
+	}
+
+	return b
+}
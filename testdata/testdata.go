@@ -92,6 +92,9 @@ func MergeMaps(a psh.EnvList, b psh.EnvList) psh.EnvList {
 	return a
 }
 
+// Reads a JSON file and base64-encodes it the way the platform encodes
+// complex variables.  Errors are printed rather than failing the test; use
+// EncodeJsonFileT() in new code.
 func EncodeJsonFile(file string) string {
 	jsonFile, err := os.Open(file)
 
@@ -100,12 +103,17 @@ func EncodeJsonFile(file string) string {
 	}
 	defer jsonFile.Close()
 
-	byteValue, _ := ioutil.ReadAll(jsonFile)
+	byteValue, err := ioutil.ReadAll(jsonFile)
+	if err != nil {
+		fmt.Println(err)
+	}
 
 	val := base64.StdEncoding.EncodeToString(byteValue)
 	return val
 }
 
+// Reads a JSON object of strings, such as ENV.json.  Errors are printed
+// rather than failing the test; use LoadJsonFileT() in new code.
 func LoadJsonFile(file string) psh.EnvList {
 	jsonFile, err := os.Open(file)
 
@@ -114,10 +122,15 @@ func LoadJsonFile(file string) psh.EnvList {
 	}
 	defer jsonFile.Close()
 
-	byteValue, _ := ioutil.ReadAll(jsonFile)
+	byteValue, err := ioutil.ReadAll(jsonFile)
+	if err != nil {
+		fmt.Println(err)
+	}
 
 	var result psh.EnvList
-	json.Unmarshal([]byte(byteValue), &result)
+	if err := json.Unmarshal([]byte(byteValue), &result); err != nil {
+		fmt.Println(err)
+	}
 
 	return result
 }
@@ -0,0 +1,211 @@
+// The vaultkms package reads relationships to the Platform.sh Vault KMS
+// service, and provides a small client for its transit secrets engine.
+//
+// Each relationship points to one endpoint of the service, with the token of
+// the policy that endpoint grants (such as encrypting or signing with a key).
+// The relationship names the policy in its "policy" key.
+package vaultkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+)
+
+// The connection details of a Vault KMS endpoint.
+type Info struct {
+	// The URL of the Vault server, such as "http://vault-kms.internal:8200".
+	BaseURL string
+
+	// The token granting the endpoint's policy.
+	Token string
+
+	// The name of the endpoint.
+	Endpoint string
+
+	// The policy the endpoint grants, such as "sign_and_verify".  The endpoint
+	// name is used if the relationship doesn't name one.
+	Policy string
+
+	// The path the transit secrets engine is mounted at, "transit" by default.
+	MountPath string
+}
+
+// Reads the connection details of a Vault KMS relationship.
+func FormattedCredentials(creds psh.Credential) (Info, error) {
+	if !strings.HasPrefix(creds.Type, "vault-kms:") {
+		return Info{}, fmt.Errorf("Not a Vault KMS relationship: %s", creds.Type)
+	}
+	if creds.Password == "" {
+		return Info{}, fmt.Errorf("The Vault KMS relationship has no token")
+	}
+
+	scheme := creds.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	mountPath := strings.Trim(creds.Path, "/")
+	if mountPath == "" {
+		mountPath = "transit"
+	}
+
+	policy := creds.Rel
+	if raw, ok := creds.Extra("policy"); ok {
+		if err := json.Unmarshal(raw, &policy); err != nil {
+			return Info{}, fmt.Errorf("Invalid Vault KMS policy: %s", err)
+		}
+	}
+
+	return Info{
+		BaseURL:   scheme + "://" + net.JoinHostPort(creds.Host, strconv.Itoa(creds.Port)),
+		Token:     creds.Password,
+		Endpoint:  creds.Rel,
+		Policy:    policy,
+		MountPath: mountPath,
+	}, nil
+}
+
+// An error returned by the Vault API.
+type Error struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *Error) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("Vault returned HTTP %d", e.StatusCode)
+	}
+	return fmt.Sprintf("Vault returned HTTP %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// A client for the transit secrets engine of a Vault KMS endpoint.
+type Client struct {
+	Info
+
+	// The HTTP client used for requests.  NewClient() sets one with a
+	// timeout.
+	HttpClient *http.Client
+}
+
+// Returns a client for a Vault KMS relationship.
+func NewClient(creds psh.Credential) (*Client, error) {
+	info, err := FormattedCredentials(creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		Info:       info,
+		HttpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Encrypts plaintext with a named key, and returns the ciphertext in Vault's
+// "vault:v1:..." format.
+func (c *Client) Encrypt(ctx context.Context, key string, plaintext []byte) (string, error) {
+	var resp struct {
+		Ciphertext string `json:"ciphertext"`
+	}
+	err := c.call(ctx, "encrypt", key, map[string]interface{}{
+		"plaintext": base64.StdEncoding.EncodeToString(plaintext),
+	}, &resp)
+
+	return resp.Ciphertext, err
+}
+
+// Decrypts ciphertext returned by Encrypt().
+func (c *Client) Decrypt(ctx context.Context, key string, ciphertext string) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"plaintext"`
+	}
+	if err := c.call(ctx, "decrypt", key, map[string]interface{}{
+		"ciphertext": ciphertext,
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// Signs input with a named key, and returns the signature in Vault's
+// "vault:v1:..." format.
+func (c *Client) Sign(ctx context.Context, key string, input []byte) (string, error) {
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	err := c.call(ctx, "sign", key, map[string]interface{}{
+		"input": base64.StdEncoding.EncodeToString(input),
+	}, &resp)
+
+	return resp.Signature, err
+}
+
+// Checks a signature returned by Sign().
+func (c *Client) Verify(ctx context.Context, key string, input []byte, signature string) (bool, error) {
+	var resp struct {
+		Valid bool `json:"valid"`
+	}
+	err := c.call(ctx, "verify", key, map[string]interface{}{
+		"input":     base64.StdEncoding.EncodeToString(input),
+		"signature": signature,
+	}, &resp)
+
+	return resp.Valid, err
+}
+
+// Send a request to a transit operation and decode the "data" of the reply.
+func (c *Client) call(ctx context.Context, operation string, key string, body interface{}, data interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	endpoint := c.BaseURL + "/v1/" + c.MountPath + "/" + operation + "/" + url.PathEscape(key)
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", c.Token)
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := c.HttpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var reply struct {
+			Errors []string `json:"errors"`
+		}
+		if json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&reply) == nil {
+			apiErr.Errors = reply.Errors
+		}
+		return apiErr
+	}
+
+	reply := struct {
+		Data interface{} `json:"data"`
+	}{Data: data}
+
+	return json.NewDecoder(resp.Body).Decode(&reply)
+}
@@ -0,0 +1,114 @@
+package vaultkms_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+	vaultkms "github.com/platformsh/config-reader-go/v2/vaultkms"
+)
+
+// A stand-in for the transit engine that "encrypts" by prefixing the
+// base64-encoded plaintext, and only accepts the sign token for signing.
+func transitServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		helper.Ok(t, json.NewDecoder(r.Body).Decode(&body))
+
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+		operation, key := parts[0], parts[1]
+
+		if (operation == "sign" || operation == "verify") && r.Header.Get("X-Vault-Token") != "s.signtoken" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors": ["permission denied"]}`))
+			return
+		}
+
+		var data map[string]interface{}
+		switch operation {
+		case "encrypt":
+			data = map[string]interface{}{"ciphertext": "vault:v1:" + key + ":" + body["plaintext"]}
+		case "decrypt":
+			data = map[string]interface{}{"plaintext": strings.TrimPrefix(body["ciphertext"], "vault:v1:"+key+":")}
+		case "sign":
+			data = map[string]interface{}{"signature": "vault:v1:sig:" + body["input"]}
+		case "verify":
+			data = map[string]interface{}{"valid": body["signature"] == "vault:v1:sig:"+body["input"]}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+}
+
+func TestVaultKmsFormattedCredentials(t *testing.T) {
+	info, err := vaultkms.FormattedCredentials(helper.ServiceCredential(t, "vault-kms", "1.6", "vault_manage"))
+	helper.Ok(t, err)
+
+	helper.Equals(t, vaultkms.Info{
+		BaseURL:   "http://vault-kms.internal:8200",
+		Token:     "s.managetoken",
+		Endpoint:  "manage_keys",
+		Policy:    "admin",
+		MountPath: "transit",
+	}, info)
+
+	info, err = vaultkms.FormattedCredentials(psh.Credential{Type: "vault-kms:1.6", Host: "vault-kms.internal", Port: 8200, Password: "s.token", Rel: "encrypt"})
+	helper.Ok(t, err)
+	helper.Equals(t, "encrypt", info.Policy)
+
+	_, err = vaultkms.FormattedCredentials(psh.Credential{Type: "mysql:10.4", Password: "x"})
+	helper.Assert(t, err != nil, "FormattedCredentials() accepted a MySQL relationship.")
+}
+
+func TestVaultKmsClientEncryptsAndDecrypts(t *testing.T) {
+	server := transitServer(t)
+	defer server.Close()
+
+	client, err := vaultkms.NewClient(helper.PointAt(t, helper.ServiceCredential(t, "vault-kms", "1.6", "vault_manage"), server))
+	helper.Ok(t, err)
+
+	ciphertext, err := client.Encrypt(context.Background(), "app-key", []byte("secret"))
+	helper.Ok(t, err)
+	helper.Equals(t, "vault:v1:app-key:c2VjcmV0", ciphertext)
+
+	plaintext, err := client.Decrypt(context.Background(), "app-key", ciphertext)
+	helper.Ok(t, err)
+	helper.Equals(t, "secret", string(plaintext))
+}
+
+func TestVaultKmsClientSignsAndVerifies(t *testing.T) {
+	server := transitServer(t)
+	defer server.Close()
+
+	client, err := vaultkms.NewClient(helper.PointAt(t, helper.ServiceCredential(t, "vault-kms", "1.6", "vault_sign"), server))
+	helper.Ok(t, err)
+
+	signature, err := client.Sign(context.Background(), "signing-key", []byte("payload"))
+	helper.Ok(t, err)
+
+	valid, err := client.Verify(context.Background(), "signing-key", []byte("payload"), signature)
+	helper.Ok(t, err)
+	helper.Assert(t, valid, "Verify() rejected a valid signature.")
+
+	valid, err = client.Verify(context.Background(), "signing-key", []byte("tampered"), signature)
+	helper.Ok(t, err)
+	helper.Assert(t, !valid, "Verify() accepted an invalid signature.")
+}
+
+func TestVaultKmsClientReportsErrors(t *testing.T) {
+	server := transitServer(t)
+	defer server.Close()
+
+	client, err := vaultkms.NewClient(helper.PointAt(t, helper.ServiceCredential(t, "vault-kms", "1.6", "vault_manage"), server))
+	helper.Ok(t, err)
+
+	_, err = client.Sign(context.Background(), "signing-key", []byte("payload"))
+	apiErr, ok := err.(*vaultkms.Error)
+	helper.Assert(t, ok, "Sign() returned %v instead of a *vaultkms.Error.", err)
+	helper.Equals(t, http.StatusForbidden, apiErr.StatusCode)
+	helper.Equals(t, []string{"permission denied"}, apiErr.Errors)
+}
@@ -0,0 +1,244 @@
+package platformconfig
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// The notification a Watcher sends its subscribers after a reload.
+type ChangeEvent struct {
+	// The configuration before and after the reload.  If the reload failed
+	// both are the configuration still in use.
+	Previous *RuntimeConfig
+	Config   *RuntimeConfig
+
+	// What changed between the two configurations, as listed by Diff(): keys
+	// are dotted paths such as "database.0.port", routes are keyed by ID, and
+	// secret values are masked.
+	Changes []Change
+
+	// Set if the reload failed, in which case Changes is empty.
+	Err error
+}
+
+// A ConfigLoader reads a fresh RuntimeConfig each time it is called.
+type ConfigLoader func() (*RuntimeConfig, error)
+
+// Returns a ConfigLoader that reads the configuration from a getter of the
+// same signature as os.Getenv().
+func EnvLoader(getter envReader, varPrefix string) ConfigLoader {
+	return func() (*RuntimeConfig, error) {
+		return NewRuntimeConfigReal(getter, varPrefix)
+	}
+}
+
+// Returns a ConfigLoader that reads environment variables from the files in a
+// directory, such as mounted secret files or a local copy of a Platform.sh
+// environment, falling back to the process environment for anything missing.
+//
+// Each file is named after the variable it holds.  Files with a `.json`
+// extension hold the decoded JSON of a complex variable, such as
+// `PLATFORM_ROUTES.json`, and are base64-encoded the way the platform does.
+// The directory is read again on every call.
+func DirLoader(dir string, varPrefix string) ConfigLoader {
+	return func() (*RuntimeConfig, error) {
+		env, err := readEnvDir(dir)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewRuntimeConfigReal(func(key string) string {
+			if val, ok := env[key]; ok {
+				return val
+			}
+			return os.Getenv(key)
+		}, varPrefix)
+	}
+}
+
+// A Watcher keeps the current RuntimeConfig of a long-running process up to
+// date, reloading it on demand or when the process receives a signal, and
+// notifies subscribers of what changed.
+//
+// A Watcher is safe for concurrent use.
+type Watcher struct {
+	load ConfigLoader
+
+	// Serializes reloads, so that events are sent in order.
+	reloading sync.Mutex
+
+	mu          sync.RWMutex
+	config      *RuntimeConfig
+	subscribers map[<-chan ChangeEvent]chan ChangeEvent
+	done        chan struct{}
+	closed      bool
+}
+
+// Creates a Watcher, loading the initial configuration straight away.
+func NewWatcher(load ConfigLoader) (*Watcher, error) {
+	config, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher{
+		load:        load,
+		config:      config,
+		subscribers: make(map[<-chan ChangeEvent]chan ChangeEvent),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Returns the current configuration.
+func (w *Watcher) Config() *RuntimeConfig {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.config
+}
+
+// Returns a channel that receives an event after every reload that changed
+// the configuration or failed.
+//
+// Events are never blocked on: a subscriber that falls more than a few events
+// behind misses the newer ones.  The channel is closed by Unsubscribe() or
+// Close().
+func (w *Watcher) Subscribe() <-chan ChangeEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	ch := make(chan ChangeEvent, 8)
+	if w.closed {
+		close(ch)
+		return ch
+	}
+	w.subscribers[ch] = ch
+
+	return ch
+}
+
+// Stops sending events to a channel returned by Subscribe(), and closes it.
+func (w *Watcher) Unsubscribe(ch <-chan ChangeEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if sub, ok := w.subscribers[ch]; ok {
+		delete(w.subscribers, ch)
+		close(sub)
+	}
+}
+
+// Reads the configuration again and notifies subscribers of the changes, if
+// any.  The changes are those listed by Diff().  If the configuration can't
+// be read the current one is kept.
+func (w *Watcher) Reload() ([]Change, error) {
+	w.reloading.Lock()
+	defer w.reloading.Unlock()
+
+	previous := w.Config()
+
+	config, err := w.load()
+	if err != nil {
+		w.notify(ChangeEvent{Previous: previous, Config: previous, Err: err})
+		return nil, err
+	}
+
+	changes := Diff(previous, config)
+
+	w.mu.Lock()
+	w.config = config
+	w.mu.Unlock()
+
+	if len(changes) > 0 {
+		w.notify(ChangeEvent{Previous: previous, Config: config, Changes: changes})
+	}
+
+	return changes, nil
+}
+
+// Reloads the configuration whenever the process receives one of the given
+// signals, or SIGHUP if none are given, until the Watcher is closed.
+func (w *Watcher) ReloadOnSignal(signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, signals...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				// Failures are reported to subscribers.
+				w.Reload()
+			case <-w.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stops reloading on signals and closes all subscriber channels.
+func (w *Watcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return
+	}
+	w.closed = true
+	close(w.done)
+
+	for ch, sub := range w.subscribers {
+		delete(w.subscribers, ch)
+		close(sub)
+	}
+}
+
+func (w *Watcher) notify(event ChangeEvent) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for _, sub := range w.subscribers {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+}
+
+// Read a directory of variable files into an EnvList.
+func readEnvDir(dir string) (EnvList, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	env := make(EnvList)
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+
+		contents, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if name := strings.TrimSuffix(file.Name(), ".json"); name != file.Name() {
+			env[name] = base64.StdEncoding.EncodeToString(contents)
+		} else {
+			env[name] = strings.TrimRight(string(contents), "\r\n")
+		}
+	}
+
+	return env, nil
+}
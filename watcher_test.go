@@ -0,0 +1,154 @@
+package platformconfig_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	psh "github.com/platformsh/config-reader-go/v2"
+	helper "github.com/platformsh/config-reader-go/v2/testdata"
+)
+
+func TestWatcherReloadReportsChanges(t *testing.T) {
+	overrides := psh.EnvList{}
+	getter := helper.RuntimeEnv(psh.EnvList{})
+
+	watcher, err := psh.NewWatcher(psh.EnvLoader(func(key string) string {
+		if val, ok := overrides[key]; ok {
+			return val
+		}
+		return getter(key)
+	}, "PLATFORM_"))
+	helper.Ok(t, err)
+	defer watcher.Close()
+
+	events := watcher.Subscribe()
+
+	changes, err := watcher.Reload()
+	helper.Ok(t, err)
+	helper.Equals(t, 0, len(changes))
+
+	overrides["PLATFORM_SMTP_HOST"] = ""
+	overrides["PLATFORM_RELATIONSHIPS"] = ""
+
+	changes, err = watcher.Reload()
+	helper.Ok(t, err)
+
+	event := <-events
+	helper.Equals(t, changes, event.Changes)
+	helper.Equals(t, watcher.Config(), event.Config)
+	helper.Equals(t, "1.2.3.4", event.Previous.SmtpHost())
+	helper.Equals(t, "", event.Config.SmtpHost())
+
+	helper.Equals(t, psh.Change{Section: "environment", Key: "smtp_host", Kind: psh.ChangeModified, Old: "1.2.3.4", New: ""}, changes[0])
+	helper.Equals(t, psh.Change{Section: "relationships", Key: "database", Kind: psh.ChangeRemoved}, changes[1])
+}
+
+func TestWatcherKeepsConfigOnFailedReload(t *testing.T) {
+	overrides := psh.EnvList{}
+	getter := helper.RuntimeEnv(psh.EnvList{})
+
+	watcher, err := psh.NewWatcher(psh.EnvLoader(func(key string) string {
+		if val, ok := overrides[key]; ok {
+			return val
+		}
+		return getter(key)
+	}, "PLATFORM_"))
+	helper.Ok(t, err)
+	defer watcher.Close()
+
+	previous := watcher.Config()
+	events := watcher.Subscribe()
+
+	overrides["PLATFORM_VARIABLES"] = "not base64"
+
+	_, err = watcher.Reload()
+	helper.Assert(t, err != nil, "Reload() accepted an invalid configuration.")
+
+	event := <-events
+	helper.Equals(t, err, event.Err)
+	helper.Assert(t, previous == watcher.Config(), "The configuration was replaced by a failed reload.")
+}
+
+func TestWatcherDirLoaderRereadsFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config-reader-go")
+	helper.Ok(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name string, contents string) {
+		helper.Ok(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+	write("PLATFORM_APPLICATION_NAME", "app\n")
+	write("PLATFORM_BRANCH", "main")
+	write("PLATFORM_VARIABLES.json", `{"api_key": "first"}`)
+
+	watcher, err := psh.NewWatcher(psh.DirLoader(dir, "PLATFORM_"))
+	helper.Ok(t, err)
+	defer watcher.Close()
+
+	helper.Equals(t, "app", watcher.Config().ApplicationName())
+	helper.Equals(t, "first", watcher.Config().Variable("api_key", ""))
+
+	write("PLATFORM_VARIABLES.json", `{"api_key": "second", "debug": "1"}`)
+
+	changes, err := watcher.Reload()
+	helper.Ok(t, err)
+
+	helper.Equals(t, []psh.Change{
+		{Section: "variables", Key: "api_key", Kind: psh.ChangeModified, Old: psh.MaskedValue, New: psh.MaskedValue},
+		{Section: "variables", Key: "debug", Kind: psh.ChangeAdded, New: psh.MaskedValue},
+	}, changes)
+	helper.Equals(t, "second", watcher.Config().Variable("api_key", ""))
+}
+
+func TestWatcherReloadsOnSignal(t *testing.T) {
+	process, err := os.FindProcess(os.Getpid())
+	helper.Ok(t, err)
+
+	overrides := psh.EnvList{}
+	getter := helper.RuntimeEnv(psh.EnvList{})
+
+	watcher, err := psh.NewWatcher(psh.EnvLoader(func(key string) string {
+		if val, ok := overrides[key]; ok {
+			return val
+		}
+		return getter(key)
+	}, "PLATFORM_"))
+	helper.Ok(t, err)
+	defer watcher.Close()
+
+	events := watcher.Subscribe()
+
+	overrides["PORT"] = "8888"
+	watcher.ReloadOnSignal(syscall.SIGHUP)
+
+	if err := process.Signal(syscall.SIGHUP); err != nil {
+		t.Skip("Signals are not supported on this platform.")
+	}
+
+	select {
+	case event := <-events:
+		helper.Equals(t, "8888", event.Config.Port())
+	case <-time.After(5 * time.Second):
+		t.Fatal("No reload after SIGHUP.")
+	}
+}
+
+func TestWatcherCloseClosesSubscriptions(t *testing.T) {
+	watcher, err := psh.NewWatcher(psh.EnvLoader(helper.RuntimeEnv(psh.EnvList{}), "PLATFORM_"))
+	helper.Ok(t, err)
+
+	first := watcher.Subscribe()
+	second := watcher.Subscribe()
+
+	watcher.Unsubscribe(first)
+	_, open := <-first
+	helper.Equals(t, false, open)
+
+	watcher.Close()
+	_, open = <-second
+	helper.Equals(t, false, open)
+}